@@ -4,14 +4,16 @@
 
 package hellosign
 
+import "context"
+
 // AccountAPI used for account manipulations.
 type AccountAPI struct {
 	*hellosign
 }
 
 // NewAccountAPI creates a new api client for account operations.
-func NewAccountAPI(apiKey string) *AccountAPI {
-	return &AccountAPI{newHellosign(apiKey)}
+func NewAccountAPI(apiKey string, opts ...Option) *AccountAPI {
+	return &AccountAPI{newHellosign(apiKey, opts...)}
 }
 
 // Acc contains information about an account and its settings.
@@ -35,17 +37,28 @@ type accRaw struct {
 
 // Get returns your Account settings.
 func (c *AccountAPI) Get() (*Acc, error) {
+	return c.GetContext(context.Background())
+}
+
+// GetContext is like Get but uses ctx for the request.
+func (c *AccountAPI) GetContext(ctx context.Context) (*Acc, error) {
 	acc := &accRaw{}
-	if err := c.getAndParse("account", nil, acc); err != nil {
+	err := c.getAndParse(ctx, "account", nil, acc)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &acc.Account, nil
+	return &acc.Account, err
 }
 
 // Update sets your account settings.
 func (c *AccountAPI) Update(callbackURL string) (*Acc, error) {
+	return c.UpdateContext(context.Background(), callbackURL)
+}
+
+// UpdateContext is like Update but uses ctx for the request.
+func (c *AccountAPI) UpdateContext(ctx context.Context, callbackURL string) (*Acc, error) {
 	acc := &accRaw{}
-	err := c.postFormAndParse("account", &struct {
+	err := c.postFormAndParse(ctx, "account", &struct {
 		CallbackURL string `form:"callback_url"`
 	}{
 		CallbackURL: callbackURL,
@@ -53,9 +66,9 @@ func (c *AccountAPI) Update(callbackURL string) (*Acc, error) {
 	return &acc.Account, err
 }
 
-func (c *AccountAPI) createOrVerify(ept, emailAddress string) (*Acc, error) {
+func (c *AccountAPI) createOrVerify(ctx context.Context, ept, emailAddress string) (*Acc, error) {
 	acc := &accRaw{}
-	err := c.postFormAndParse(ept, &struct {
+	err := c.postFormAndParse(ctx, ept, &struct {
 		EmailAddress string `form:"email_address"`
 	}{
 		EmailAddress: emailAddress,
@@ -65,10 +78,20 @@ func (c *AccountAPI) createOrVerify(ept, emailAddress string) (*Acc, error) {
 
 // Create signs up for a new HelloSign Account.
 func (c *AccountAPI) Create(emailAddress string) (*Acc, error) {
-	return c.createOrVerify("account/create", emailAddress)
+	return c.CreateContext(context.Background(), emailAddress)
+}
+
+// CreateContext is like Create but uses ctx for the request.
+func (c *AccountAPI) CreateContext(ctx context.Context, emailAddress string) (*Acc, error) {
+	return c.createOrVerify(ctx, "account/create", emailAddress)
 }
 
 // Verify whether a HelloSign Account exists.
 func (c *AccountAPI) Verify(emailAddress string) (*Acc, error) {
-	return c.createOrVerify("account/verify", emailAddress)
+	return c.VerifyContext(context.Background(), emailAddress)
+}
+
+// VerifyContext is like Verify but uses ctx for the request.
+func (c *AccountAPI) VerifyContext(ctx context.Context, emailAddress string) (*Acc, error) {
+	return c.createOrVerify(ctx, "account/verify", emailAddress)
 }
@@ -5,7 +5,7 @@
 package hellosign
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"net/http"
 )
@@ -16,8 +16,8 @@ type APIAppAPI struct {
 }
 
 // NewAPIAppAPI creates a new api client for api app operations.
-func NewAPIAppAPI(apiKey string) *APIAppAPI {
-	return &APIAppAPI{newHellosign(apiKey)}
+func NewAPIAppAPI(apiKey string, opts ...Option) *APIAppAPI {
+	return &APIAppAPI{newHellosign(apiKey, opts...)}
 }
 
 // APIApp Contains information about an API App.
@@ -31,7 +31,7 @@ type APIApp struct {
 	Oauth       *struct {
 		CallbackURL string   `json:"callback_url"`
 		Scopes      []string `json:"scopes"`
-		Secret      string   `json:"string"`
+		Secret      string   `json:"secret"`
 	} `json:"oauth"`
 	OwnerAccount struct {
 		AccountID    string `json:"account_id"`
@@ -45,8 +45,13 @@ type apiAppRaw struct {
 
 // Get returns a struct with information about an API App.
 func (c *APIAppAPI) Get(clientID string) (*APIApp, error) {
+	return c.GetContext(context.Background(), clientID)
+}
+
+// GetContext is like Get but uses ctx for the request.
+func (c *APIAppAPI) GetContext(ctx context.Context, clientID string) (*APIApp, error) {
 	app := &apiAppRaw{}
-	err := c.getAndParse(fmt.Sprintf("api_app/%s", clientID), nil, app)
+	err := c.getAndParse(ctx, fmt.Sprintf("api_app/%s", clientID), nil, app)
 	return &app.APIApp, err
 }
 
@@ -59,11 +64,31 @@ type APIAppLst struct {
 // List returns a list of API Apps that are accessible by you. If you are on a team with an Admin
 // or Developer role, this list will include apps owned by teammates.
 func (c *APIAppAPI) List(parms ListParms) (*APIAppLst, error) {
+	return c.ListContext(context.Background(), parms)
+}
+
+// ListContext is like List but uses ctx for the request.
+func (c *APIAppAPI) ListContext(ctx context.Context, parms ListParms) (*APIAppLst, error) {
 	lst := &APIAppLst{}
-	err := c.list("api_app/list", parms, lst)
+	err := c.list(ctx, "api_app/list", parms, lst)
 	return lst, err
 }
 
+// ListAll returns an iterator over the API Apps of all pages, starting at parms.Page.
+func (c *APIAppAPI) ListAll(ctx context.Context, parms ListParms, opts ...ListOption) *APIAppIter {
+	return &APIAppIter{newListIter(ctx, parms, func(ctx context.Context, parms ListParms) (ListInfo, []interface{}, error) {
+		lst, err := c.ListContext(ctx, parms)
+		if err != nil {
+			return ListInfo{}, nil, err
+		}
+		items := make([]interface{}, len(lst.APIApps))
+		for i, v := range lst.APIApps {
+			items[i] = v
+		}
+		return lst.ListInfo, items, nil
+	}, opts)}
+}
+
 // APIAppCreateParms parameters for creating an api app.
 type APIAppCreateParms struct {
 	Name                 string             `form:"name"`
@@ -82,11 +107,17 @@ type APIAppCreateOauth struct {
 
 // Create Creates a new API App.
 func (c *APIAppAPI) Create(parms APIAppCreateParms) (*APIApp, error) {
+	return c.CreateContext(context.Background(), parms)
+}
+
+// CreateContext is like Create but uses ctx for the request.
+func (c *APIAppAPI) CreateContext(ctx context.Context, parms APIAppCreateParms) (*APIApp, error) {
 	app := &apiAppRaw{}
-	if err := c.postFormAndParse("api_app", &parms, app); err != nil {
+	err := c.postFormAndParse(ctx, "api_app", &parms, app)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &app.APIApp, nil
+	return &app.APIApp, err
 }
 
 // APIAppUpdateParms parameters for updating an api app.
@@ -108,21 +139,33 @@ type APIAppUpdateOauth struct {
 // Update Updates an existing API App. Can only be invoked for apps you own. Only the fields you
 // provide will be updated. If you wish to clear an existing optional field, provide an empty string.
 func (c *APIAppAPI) Update(clientID string, parms APIAppUpdateParms) (*APIApp, error) {
+	return c.UpdateContext(context.Background(), clientID, parms)
+}
+
+// UpdateContext is like Update but uses ctx for the request.
+func (c *APIAppAPI) UpdateContext(ctx context.Context, clientID string, parms APIAppUpdateParms) (*APIApp, error) {
 	app := &apiAppRaw{}
-	if err := c.postFormAndParse(fmt.Sprintf("api_app/%s", clientID), &parms, app); err != nil {
+	err := c.postFormAndParse(ctx, fmt.Sprintf("api_app/%s", clientID), &parms, app)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &app.APIApp, nil
+	return &app.APIApp, err
 }
 
 // Delete deletes an API App. Can only be invoked for apps you own.
 func (c *APIAppAPI) Delete(clientID string) (bool, error) {
-	resp, err := c.delete(fmt.Sprintf("api_app/%s", clientID))
+	return c.DeleteContext(context.Background(), clientID)
+}
+
+// DeleteContext is like Delete but uses ctx for the request.
+func (c *APIAppAPI) DeleteContext(ctx context.Context, clientID string) (bool, error) {
+	resp, err := c.delete(ctx, fmt.Sprintf("api_app/%s", clientID))
 	if err != nil {
 		return false, err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusNoContent {
-		return false, errors.New(resp.Status)
+		return false, newResponseErr(resp, nil, ErrUnexpectedStatus)
 	}
 	return true, nil
 }
@@ -0,0 +1,213 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Suffixes of the signer columns in bulk send csv files, the column name starts with the signer role.
+const (
+	bulkNameSuffix         = "_name"
+	bulkEmailAddressSuffix = "_email_address"
+	bulkPinSuffix          = "_pin"
+)
+
+// BulkSigner holds the signers, by signer role, and custom field values of one signature request of a bulk send.
+type BulkSigner struct {
+	Signers      map[string]SigReqSendTplParmsSigner `form:"signers"`
+	CustomFields []BulkCustomField                   `form:"custom_fields,omitempty"`
+}
+
+// BulkCustomField is the value of a template custom field for one signature request of a bulk send.
+type BulkCustomField struct {
+	Name  string `form:"name"`
+	Value string `form:"value"`
+}
+
+// SigReqBulkSendTplParms parameters for sending a signature request based on templates to every entry of a signer list.
+type SigReqBulkSendTplParms struct {
+	TemplateIds           []string                         `form:"template_ids"`
+	SignerList            []BulkSigner                     `form:"signer_list"`
+	Ccs                   map[string]SigReqSendTplParmsCcs `form:"ccs,omitempty"`
+	Title                 string                           `form:"title,omitempty"`
+	Subject               string                           `form:"subject,omitempty"`
+	Message               string                           `form:"message,omitempty"`
+	Metadata              map[string]string                `form:"metadata,omitempty"`
+	TestMode              int8                             `form:"test_mode,omitempty"`
+	AllowDecline          int8                             `form:"allow_decline,omitempty"`
+	SigningRedirectURL    string                           `form:"signing_redirect_url,omitempty"`
+	RequestingRedirectURL string                           `form:"requesting_redirect_url,omitempty"`
+	ClientID              string                           `form:"client_id,omitempty"`
+	Attachments           []SignerAttachment               `form:"attachments,omitempty"`
+}
+
+// ReadBulkSigners reads a signer list from csv. The first row names the columns, columns named
+// <role>_name, <role>_email_address and <role>_pin hold the signer of a role, where role is one of
+// the signer roles of the templates. All other columns, such as company_name when Company is not a
+// role, hold custom field values. Empty custom field values are left out.
+func ReadBulkSigners(r io.Reader, roles []string) ([]BulkSigner, error) {
+	knownRoles := map[string]bool{}
+	for _, role := range roles {
+		knownRoles[role] = true
+	}
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("Signer csv is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	signers := []BulkSigner{}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return signers, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, bulkSignerFromRow(knownRoles, header, row))
+	}
+}
+
+// signerColumn returns the role and suffix of a signer column, ok is false for custom field columns.
+func signerColumn(knownRoles map[string]bool, col string) (role, suffix string, ok bool) {
+	for _, suffix := range []string{bulkEmailAddressSuffix, bulkNameSuffix, bulkPinSuffix} {
+		if role := strings.TrimSuffix(col, suffix); role != col && knownRoles[role] {
+			return role, suffix, true
+		}
+	}
+	return "", "", false
+}
+
+func bulkSignerFromRow(knownRoles map[string]bool, header, row []string) BulkSigner {
+	bs := BulkSigner{Signers: map[string]SigReqSendTplParmsSigner{}}
+	for i, col := range header {
+		v := strings.TrimSpace(row[i])
+		col = strings.TrimSpace(col)
+		role, suffix, ok := signerColumn(knownRoles, col)
+		if !ok {
+			if v != "" {
+				bs.CustomFields = append(bs.CustomFields, BulkCustomField{Name: col, Value: v})
+			}
+			continue
+		}
+		s := bs.Signers[role]
+		switch suffix {
+		case bulkEmailAddressSuffix:
+			s.EmailAddress = v
+		case bulkNameSuffix:
+			s.Name = v
+		case bulkPinSuffix:
+			s.Pin = v
+		}
+		bs.Signers[role] = s
+	}
+	return bs
+}
+
+// validateBulkSigners checks that every entry has a signer with name and email address for each of
+// the roles, no signers for other roles and only known custom fields.
+func validateBulkSigners(tpls []*Tpl, signerList []BulkSigner) error {
+	roles := map[string]bool{}
+	customFields := map[string]bool{}
+	for _, tpl := range tpls {
+		for _, r := range tpl.SignerRoles {
+			roles[r.Name] = true
+		}
+		for _, d := range tpl.Documents {
+			for _, cf := range d.CustomFields {
+				customFields[cf.Name] = true
+			}
+		}
+	}
+	for i, bs := range signerList {
+		for role := range roles {
+			s, ok := bs.Signers[role]
+			if !ok {
+				return fmt.Errorf("Signer list entry %d: no signer for role %s", i+1, role)
+			}
+			if s.Name == "" || s.EmailAddress == "" {
+				return fmt.Errorf("Signer list entry %d: specify name and email address for role %s", i+1, role)
+			}
+		}
+		for role := range bs.Signers {
+			if !roles[role] {
+				return fmt.Errorf("Signer list entry %d: role %s is not a signer role of the templates", i+1, role)
+			}
+		}
+		for _, cf := range bs.CustomFields {
+			if !customFields[cf.Name] {
+				return fmt.Errorf("Signer list entry %d: %s is not a custom field of the templates", i+1, cf.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// BulkSendWithTemplate creates a BulkSendJob of signature requests based on the templates, one for every
+// entry of the signer list. The signer list is validated against the signer roles and custom fields of
+// the templates before it is sent.
+func (c *SignatureRequestAPI) BulkSendWithTemplate(parms SigReqBulkSendTplParms) (*BulkSendJob, error) {
+	return c.BulkSendWithTemplateContext(context.Background(), parms)
+}
+
+// BulkSendWithTemplateContext is like BulkSendWithTemplate but uses ctx for the requests.
+func (c *SignatureRequestAPI) BulkSendWithTemplateContext(ctx context.Context, parms SigReqBulkSendTplParms) (*BulkSendJob, error) {
+	return c.bulkSend(ctx, "signature_request/bulk_send_with_template", parms)
+}
+
+// BulkCreateEmbeddedWithTemplate is like BulkSendWithTemplate but creates embedded signature requests,
+// to be signed in an embedded iFrame. The client id is required.
+func (c *SignatureRequestAPI) BulkCreateEmbeddedWithTemplate(parms SigReqBulkSendTplParms) (*BulkSendJob, error) {
+	return c.BulkCreateEmbeddedWithTemplateContext(context.Background(), parms)
+}
+
+// BulkCreateEmbeddedWithTemplateContext is like BulkCreateEmbeddedWithTemplate but uses ctx for the requests.
+func (c *SignatureRequestAPI) BulkCreateEmbeddedWithTemplateContext(ctx context.Context, parms SigReqBulkSendTplParms) (*BulkSendJob, error) {
+	if parms.ClientID == "" {
+		return nil, errors.New("Specify client id")
+	}
+	return c.bulkSend(ctx, "signature_request/bulk_create_embedded_with_template", parms)
+}
+
+func (c *SignatureRequestAPI) bulkSend(ctx context.Context, ept string, parms SigReqBulkSendTplParms) (*BulkSendJob, error) {
+	if len(parms.TemplateIds) == 0 {
+		return nil, errors.New("Specify template ids, none given")
+	}
+	if len(parms.SignerList) == 0 {
+		return nil, errors.New("Specify signer list, none given")
+	}
+	tplAPI := &TemplateAPI{c.hellosign}
+	tpls := make([]*Tpl, len(parms.TemplateIds))
+	for i, id := range parms.TemplateIds {
+		tpl, err := tplAPI.GetContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		tpls[i] = tpl
+	}
+	if err := validateBulkSigners(tpls, parms.SignerList); err != nil {
+		return nil, err
+	}
+	// Every entry has a signer for each role of the templates.
+	if err := validateAttachments(parms.Attachments, len(parms.SignerList[0].Signers)); err != nil {
+		return nil, err
+	}
+	job := &bulkSendJobRaw{}
+	err := c.postFormAndParse(ctx, ept, &parms, job)
+	if err != nil && !isWarning(err) {
+		return nil, err
+	}
+	return &job.BulkSendJob, err
+}
@@ -0,0 +1,205 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ajg/form"
+)
+
+// BulkSendJobAPI used for tracking bulk send jobs.
+type BulkSendJobAPI struct {
+	*hellosign
+}
+
+// NewBulkSendJobAPI creates a new api client for bulk send job operations.
+func NewBulkSendJobAPI(apiKey string, opts ...Option) *BulkSendJobAPI {
+	return &BulkSendJobAPI{newHellosign(apiKey, opts...)}
+}
+
+// BulkSendJob is a batch of signature requests sent with one call.
+type BulkSendJob struct {
+	BulkSendJobID string `json:"bulk_send_job_id"`
+	Total         uint64 `json:"total"` // Number of signature requests in the job
+	IsCreator     bool   `json:"is_creator"`
+	CreatedAt     uint64 `json:"created_at"`
+}
+
+type bulkSendJobRaw struct {
+	BulkSendJob BulkSendJob `json:"bulk_send_job"`
+}
+
+// BulkSendJobSigReqs is a bulk send job with a page of its signature requests.
+type BulkSendJobSigReqs struct {
+	BulkSendJob       BulkSendJob `json:"bulk_send_job"`
+	ListInfo          ListInfo    `json:"list_info"`
+	SignatureRequests []SigReq    `json:"signature_requests"`
+}
+
+// Get returns the bulk send job with the page of its signature requests given by parms. Only the paging
+// parameters of parms are used.
+func (c *BulkSendJobAPI) Get(bulkSendJobID string, parms ListParms) (*BulkSendJobSigReqs, error) {
+	return c.GetContext(context.Background(), bulkSendJobID, parms)
+}
+
+// GetContext is like Get but uses ctx for the request.
+func (c *BulkSendJobAPI) GetContext(ctx context.Context, bulkSendJobID string, parms ListParms) (*BulkSendJobSigReqs, error) {
+	parmString, err := pageParms(parms)
+	if err != nil {
+		return nil, err
+	}
+	job := &BulkSendJobSigReqs{}
+	err = c.getAndParse(ctx, fmt.Sprintf("bulk_send_job/%s", bulkSendJobID), &parmString, job)
+	if err != nil && !isWarning(err) {
+		return nil, err
+	}
+	return job, err
+}
+
+// pageParms encodes the paging parameters of parms, the bulk send job endpoints accept no others.
+func pageParms(parms ListParms) (string, error) {
+	return form.EncodeToString(struct {
+		Page     uint64 `form:"page,omitempty"`
+		PageSize uint64 `form:"page_size,omitempty"`
+	}{parms.Page, parms.PageSize})
+}
+
+// GetAll returns an iterator over the signature requests of the bulk send job across all pages,
+// starting at parms.Page. Only the paging parameters of parms are used.
+func (c *BulkSendJobAPI) GetAll(ctx context.Context, bulkSendJobID string, parms ListParms, opts ...ListOption) *SigReqIter {
+	parms = ListParms{Page: parms.Page, PageSize: parms.PageSize}
+	return &SigReqIter{newListIter(ctx, parms, func(ctx context.Context, parms ListParms) (ListInfo, []interface{}, error) {
+		job, err := c.GetContext(ctx, bulkSendJobID, parms)
+		if err != nil {
+			return ListInfo{}, nil, err
+		}
+		items := make([]interface{}, len(job.SignatureRequests))
+		for i, v := range job.SignatureRequests {
+			items[i] = v
+		}
+		return job.ListInfo, items, nil
+	}, opts)}
+}
+
+// BulkSendJobLst is a list of bulk send jobs of this account.
+type BulkSendJobLst struct {
+	ListInfo     ListInfo      `json:"list_info"`
+	BulkSendJobs []BulkSendJob `json:"bulk_send_jobs"`
+}
+
+// List returns a list of the bulk send jobs the account can access.
+func (c *BulkSendJobAPI) List(parms ListParms) (*BulkSendJobLst, error) {
+	return c.ListContext(context.Background(), parms)
+}
+
+// ListContext is like List but uses ctx for the request.
+func (c *BulkSendJobAPI) ListContext(ctx context.Context, parms ListParms) (*BulkSendJobLst, error) {
+	parmString, err := pageParms(parms)
+	if err != nil {
+		return nil, err
+	}
+	lst := &BulkSendJobLst{}
+	err = c.getAndParse(ctx, "bulk_send_job/list", &parmString, lst)
+	if err != nil && !isWarning(err) {
+		return nil, err
+	}
+	return lst, err
+}
+
+// BulkSendJobProgress counts the signature requests of a bulk send job by state. A signature request is
+// pending until it has been created and is complete, declined or failed.
+type BulkSendJobProgress struct {
+	BulkSendJob     BulkSendJob
+	Created         uint64 // Signature requests created so far, including failed ones
+	AwaitingSigners uint64 // Created signature requests that are not complete, declined or failed
+	Complete        uint64
+	Declined        uint64
+	Failed          uint64
+}
+
+// Sent reports whether all signature requests of the job have been created. Requests that failed
+// to send count as created and are reported in Failed.
+func (p BulkSendJobProgress) Sent() bool {
+	return p.Created >= p.BulkSendJob.Total
+}
+
+// Done reports whether every signature request of the job has left the pending state. This can take
+// as long as the signers need.
+func (p BulkSendJobProgress) Done() bool {
+	return p.Sent() && p.AwaitingSigners == 0
+}
+
+// Progress walks all signature requests of the bulk send job and counts them by state.
+func (c *BulkSendJobAPI) Progress(bulkSendJobID string) (*BulkSendJobProgress, error) {
+	return c.ProgressContext(context.Background(), bulkSendJobID)
+}
+
+// ProgressContext is like Progress but uses ctx for the requests.
+func (c *BulkSendJobAPI) ProgressContext(ctx context.Context, bulkSendJobID string) (*BulkSendJobProgress, error) {
+	p := &BulkSendJobProgress{}
+	var job *BulkSendJob
+	it := newListIter(ctx, ListParms{}, func(ctx context.Context, parms ListParms) (ListInfo, []interface{}, error) {
+		page, err := c.GetContext(ctx, bulkSendJobID, parms)
+		if err != nil {
+			return ListInfo{}, nil, err
+		}
+		job = &page.BulkSendJob
+		items := make([]interface{}, len(page.SignatureRequests))
+		for i, v := range page.SignatureRequests {
+			items[i] = v
+		}
+		return page.ListInfo, items, nil
+	}, nil)
+	defer it.Close()
+	for it.Next() {
+		sigReq := it.cur.(SigReq)
+		p.Created++
+		switch {
+		case sigReq.HasError:
+			p.Failed++
+		case sigReq.IsDeclined:
+			p.Declined++
+		case sigReq.IsComplete:
+			p.Complete++
+		default:
+			p.AwaitingSigners++
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if job != nil {
+		p.BulkSendJob = *job
+	}
+	return p, nil
+}
+
+// Wait polls the progress of the bulk send job every interval until every signature request has left
+// the pending state. When progress is not nil it is called with the result of every poll.
+func (c *BulkSendJobAPI) Wait(bulkSendJobID string, interval time.Duration, progress func(BulkSendJobProgress)) (*BulkSendJobProgress, error) {
+	return c.WaitContext(context.Background(), bulkSendJobID, interval, progress)
+}
+
+// WaitContext is like Wait but uses ctx for the requests and stops waiting when ctx is done.
+func (c *BulkSendJobAPI) WaitContext(ctx context.Context, bulkSendJobID string, interval time.Duration, progress func(BulkSendJobProgress)) (*BulkSendJobProgress, error) {
+	for {
+		p, err := c.ProgressContext(ctx, bulkSendJobID)
+		if err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			progress(*p)
+		}
+		if p.Done() {
+			return p, nil
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return p, err
+		}
+	}
+}
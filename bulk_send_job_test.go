@@ -0,0 +1,137 @@
+package hellosign_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BulkSendJobAPI", func() {
+	const jobJSON = `{"bulk_send_job_id": "job-1", "total": 3, "is_creator": true, "created_at": 1532640962}`
+
+	var (
+		api *hellosign.BulkSendJobAPI
+		// Signature requests returned for the job, one page per element.
+		pages [][]string
+		polls int
+	)
+
+	_ = BeforeEach(func() {
+		api = hellosign.NewBulkSendJobAPI("asdf")
+		pages = nil
+		polls = 0
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("bulk_send_job/job-1"),
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.URL.Query()).ToNot(HaveKey("account_id"))
+				Expect(req.URL.Query()).ToNot(HaveKey("query"))
+				page, err := strconv.Atoi(req.URL.Query().Get("page"))
+				Expect(err).To(BeNil())
+				if page == 1 {
+					polls++
+				}
+				return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{
+					"bulk_send_job": %s,
+					"list_info": {"page": %d, "num_pages": %d, "num_results": 3, "page_size": 2},
+					"signature_requests": [%s]
+				}`, jobJSON, page, len(pages), strings.Join(pages[page-1], ","))), nil
+			})
+	})
+
+	sigReq := func(id, state string) string {
+		return fmt.Sprintf(`{"signature_request_id": "%s", "bulk_send_job_id": "job-1", "%s": true}`, id, state)
+	}
+
+	It("gets a page of the signature requests of a job", func() {
+		pages = [][]string{{sigReq("a", "is_complete"), sigReq("b", "is_declined")}, {sigReq("c", "has_error")}}
+		job, err := api.Get("job-1", hellosign.ListParms{AccountID: "acc-1", Page: 2, PageSize: 2, Query: "title:contract"})
+		Expect(err).To(BeNil())
+		Expect(job.BulkSendJob.BulkSendJobID).To(Equal("job-1"))
+		Expect(job.BulkSendJob.Total).To(Equal(uint64(3)))
+		Expect(job.ListInfo.Page).To(Equal(uint64(2)))
+		Expect(job.SignatureRequests).To(HaveLen(1))
+		Expect(*job.SignatureRequests[0].BulkSendJobID).To(Equal("job-1"))
+
+		ids := []string{}
+		it := api.GetAll(context.Background(), "job-1", hellosign.ListParms{Query: "title:contract"})
+		for it.Next() {
+			ids = append(ids, it.Value().SignatureRequestID)
+		}
+		Expect(it.Err()).To(BeNil())
+		Expect(ids).To(Equal([]string{"a", "b", "c"}))
+	})
+
+	It("lists jobs", func() {
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("bulk_send_job/list"),
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.URL.Query().Get("page_size")).To(Equal("10"))
+				return httpmock.NewStringResponse(http.StatusOK, `{
+					"list_info": {"page": 1, "num_pages": 1, "num_results": 1, "page_size": 10},
+					"bulk_send_jobs": [`+jobJSON+`]
+				}`), nil
+			})
+		lst, err := api.List(hellosign.ListParms{PageSize: 10})
+		Expect(err).To(BeNil())
+		Expect(lst.ListInfo.NumResults).To(Equal(uint64(1)))
+		Expect(lst.BulkSendJobs).To(HaveLen(1))
+		Expect(lst.BulkSendJobs[0].CreatedAt).To(Equal(uint64(1532640962)))
+	})
+
+	It("counts signature requests by state", func() {
+		pages = [][]string{{sigReq("a", "is_complete"), sigReq("b", "is_declined")}, {sigReq("c", "has_error")}}
+		p, err := api.Progress("job-1")
+		Expect(err).To(BeNil())
+		Expect(*p).To(Equal(hellosign.BulkSendJobProgress{
+			BulkSendJob: hellosign.BulkSendJob{BulkSendJobID: "job-1", Total: 3, IsCreator: true, CreatedAt: 1532640962},
+			Created:     3,
+			Complete:    1,
+			Declined:    1,
+			Failed:      1,
+		}))
+		Expect(p.Sent()).To(BeTrue())
+		Expect(p.Done()).To(BeTrue())
+	})
+
+	It("waits until no signature request is pending", func() {
+		pages = [][]string{{sigReq("a", "is_complete"), sigReq("b", "is_pending")}}
+		seen := []hellosign.BulkSendJobProgress{}
+		p, err := api.Wait("job-1", time.Millisecond, func(p hellosign.BulkSendJobProgress) {
+			seen = append(seen, p)
+			switch len(seen) {
+			case 1:
+				pages = [][]string{{sigReq("a", "is_complete"), sigReq("b", "is_pending")}, {sigReq("c", "has_error")}}
+			case 2:
+				pages = [][]string{{sigReq("a", "is_complete"), sigReq("b", "is_declined")}, {sigReq("c", "has_error")}}
+			}
+		})
+		Expect(err).To(BeNil())
+		Expect(p.Done()).To(BeTrue())
+		Expect(p.AwaitingSigners).To(Equal(uint64(0)))
+		Expect(p.Failed).To(Equal(uint64(1)))
+		Expect(polls).To(Equal(3))
+		Expect(seen).To(HaveLen(3))
+		Expect(seen[0].Sent()).To(BeFalse())
+		// All requests are created, but one is still waiting for its signer.
+		Expect(seen[1].Created).To(Equal(seen[1].BulkSendJob.Total))
+		Expect(seen[1].Sent()).To(BeTrue())
+		Expect(seen[1].AwaitingSigners).To(Equal(uint64(1)))
+		Expect(seen[1].Done()).To(BeFalse())
+	})
+
+	It("stops waiting when the context is done", func() {
+		pages = [][]string{{sigReq("a", "is_pending")}}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := api.WaitContext(ctx, "job-1", 5*time.Millisecond, nil)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(polls).To(BeNumerically(">", 1))
+	})
+})
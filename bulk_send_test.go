@@ -0,0 +1,175 @@
+package hellosign_test
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bulk send", func() {
+	const (
+		tplResp = `{
+			"template": {
+				"template_id": "tpl-1",
+				"signer_roles": [{"name": "Employee"}, {"name": "Manager"}],
+				"documents": [{"index": 0, "custom_fields": [{"name": "Salary", "type": "text"}, {"name": "company_name", "type": "text"}]}]
+			}
+		}`
+		jobResp = `{
+			"bulk_send_job": {
+				"bulk_send_job_id": "job-1",
+				"total": 2,
+				"is_creator": true,
+				"created_at": 1532640962
+			}
+		}`
+		signerCSV = `Employee_name,Employee_email_address,Manager_name,Manager_email_address,Manager_pin,Salary
+Jack,jack@example.com,Boss,boss@example.com,1234,"$50,000"
+Jill,jill@example.com,Boss,boss@example.com,,
+`
+	)
+
+	roles := []string{"Employee", "Manager"}
+
+	var (
+		api    *hellosign.SignatureRequestAPI
+		params map[string]string
+		posted bool
+	)
+
+	_ = BeforeEach(func() {
+		api = hellosign.NewSignatureRequestAPI("asdf")
+		params = nil
+		posted = false
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("template/tpl-1"),
+			httpmock.NewStringResponder(http.StatusOK, tplResp))
+	})
+
+	respond := func(ept string) {
+		httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL(ept),
+			func(req *http.Request) (*http.Response, error) {
+				var err error
+				posted = true
+				params, err = parseRequestParameters(req)
+				Expect(err).To(BeNil())
+				return httpmock.NewStringResponse(http.StatusOK, jobResp), nil
+			})
+	}
+
+	readSigners := func(s string) []hellosign.BulkSigner {
+		signers, err := hellosign.ReadBulkSigners(strings.NewReader(s), roles)
+		Expect(err).To(BeNil())
+		return signers
+	}
+
+	It("reads signer lists from csv", func() {
+		signers := readSigners(signerCSV)
+		Expect(signers).To(HaveLen(2))
+		Expect(signers[0].Signers).To(Equal(map[string]hellosign.SigReqSendTplParmsSigner{
+			"Employee": {Name: "Jack", EmailAddress: "jack@example.com"},
+			"Manager":  {Name: "Boss", EmailAddress: "boss@example.com", Pin: "1234"},
+		}))
+		Expect(signers[0].CustomFields).To(Equal([]hellosign.BulkCustomField{{Name: "Salary", Value: "$50,000"}}))
+		Expect(signers[1].CustomFields).To(BeEmpty())
+
+		_, err := hellosign.ReadBulkSigners(strings.NewReader(""), roles)
+		Expect(err).ToNot(BeNil())
+		_, err = hellosign.ReadBulkSigners(strings.NewReader("Employee_name,Employee_email_address\nJack\n"), roles)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("reads columns not prefixed by a signer role as custom fields", func() {
+		signers := readSigners("Employee_name,Employee_email_address,Manager_name,Manager_email_address,company_name\n" +
+			"Jack,jack@example.com,Boss,boss@example.com,Acme\n")
+		Expect(signers[0].Signers).To(HaveLen(2))
+		Expect(signers[0].CustomFields).To(Equal([]hellosign.BulkCustomField{{Name: "company_name", Value: "Acme"}}))
+
+		respond("signature_request/bulk_send_with_template")
+		_, err := api.BulkSendWithTemplate(hellosign.SigReqBulkSendTplParms{
+			TemplateIds: []string{"tpl-1"},
+			SignerList:  signers,
+		})
+		Expect(err).To(BeNil())
+		Expect(params["signer_list[0][custom_fields][0][name]"]).To(Equal("company_name"))
+		Expect(params).ToNot(HaveKey("signer_list[0][signers][company][name]"))
+	})
+
+	It("sends signature requests to a signer list", func() {
+		respond("signature_request/bulk_send_with_template")
+		job, err := api.BulkSendWithTemplate(hellosign.SigReqBulkSendTplParms{
+			TemplateIds: []string{"tpl-1"},
+			SignerList:  readSigners(signerCSV),
+			Ccs: map[string]hellosign.SigReqSendTplParmsCcs{
+				"HR": {EmailAddress: "hr@example.com"},
+			},
+			Subject:     "Your contract",
+			TestMode:    1,
+			Attachments: []hellosign.SignerAttachment{{Name: "Id card", SignerIndex: 1}},
+		})
+		Expect(err).To(BeNil())
+		Expect(job.BulkSendJobID).To(Equal("job-1"))
+		Expect(job.Total).To(Equal(uint64(2)))
+		Expect(job.IsCreator).To(BeTrue())
+		Expect(job.CreatedAt).To(Equal(uint64(1532640962)))
+
+		Expect(params["template_ids[0]"]).To(Equal("tpl-1"))
+		Expect(params["signer_list[0][signers][Employee][name]"]).To(Equal("Jack"))
+		Expect(params["signer_list[0][signers][Employee][email_address]"]).To(Equal("jack@example.com"))
+		Expect(params["signer_list[0][signers][Manager][pin]"]).To(Equal("1234"))
+		Expect(params["signer_list[0][custom_fields][0][name]"]).To(Equal("Salary"))
+		Expect(params["signer_list[0][custom_fields][0][value]"]).To(Equal("$50,000"))
+		Expect(params["signer_list[1][signers][Employee][name]"]).To(Equal("Jill"))
+		Expect(params).ToNot(HaveKey("signer_list[1][signers][Manager][pin]"))
+		Expect(params["ccs[HR][email_address]"]).To(Equal("hr@example.com"))
+		Expect(params["subject"]).To(Equal("Your contract"))
+		Expect(params["test_mode"]).To(Equal("1"))
+		Expect(params["attachments[0][name]"]).To(Equal("Id card"))
+		Expect(params["attachments[0][signer_index]"]).To(Equal("1"))
+	})
+
+	It("creates embedded signature requests for a signer list", func() {
+		respond("signature_request/bulk_create_embedded_with_template")
+		parms := hellosign.SigReqBulkSendTplParms{
+			TemplateIds: []string{"tpl-1"},
+			SignerList:  readSigners(signerCSV),
+		}
+		_, err := api.BulkCreateEmbeddedWithTemplate(parms)
+		Expect(err).ToNot(BeNil())
+		Expect(posted).To(BeFalse())
+
+		parms.ClientID = "client-id"
+		job, err := api.BulkCreateEmbeddedWithTemplate(parms)
+		Expect(err).To(BeNil())
+		Expect(job.BulkSendJobID).To(Equal("job-1"))
+		Expect(params["client_id"]).To(Equal("client-id"))
+	})
+
+	It("validates signer lists against the templates before sending", func() {
+		respond("signature_request/bulk_send_with_template")
+		for _, s := range []string{
+			"Employee_name,Employee_email_address\nJack,jack@example.com\n",
+			"Employee_name,Employee_email_address,Manager_name,Manager_email_address\nJack,jack@example.com,Boss,\n",
+			"Employee_name,Employee_email_address,Manager_name,Manager_email_address,Witness_name,Witness_email_address\nJack,jack@example.com,Boss,boss@example.com,W,w@example.com\n",
+			"Employee_name,Employee_email_address,Manager_name,Manager_email_address,Bonus\nJack,jack@example.com,Boss,boss@example.com,100\n",
+		} {
+			_, err := api.BulkSendWithTemplate(hellosign.SigReqBulkSendTplParms{
+				TemplateIds: []string{"tpl-1"},
+				SignerList:  readSigners(s),
+			})
+			Expect(err).ToNot(BeNil())
+		}
+		_, err := api.BulkSendWithTemplate(hellosign.SigReqBulkSendTplParms{TemplateIds: []string{"tpl-1"}})
+		Expect(err).ToNot(BeNil())
+		_, err = api.BulkSendWithTemplate(hellosign.SigReqBulkSendTplParms{
+			TemplateIds: []string{"tpl-1"},
+			SignerList:  readSigners(signerCSV),
+			Attachments: []hellosign.SignerAttachment{{Name: "Id card", SignerIndex: 2}},
+		})
+		Expect(err).ToNot(BeNil())
+		Expect(posted).To(BeFalse())
+	})
+})
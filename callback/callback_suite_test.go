@@ -0,0 +1,33 @@
+package callback_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCallback(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Callback Suite")
+}
+
+func newCallbackRequest(payload string) *http.Request {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	Expect(w.WriteField("json", payload)).To(Succeed())
+	Expect(w.Close()).To(Succeed())
+	req := httptest.NewRequest(http.MethodPost, "/hellosign/callback", &b)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func serveHandler(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,298 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package callback
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupStore records the hashes of handled events. HelloSign sends events again until they are
+// acknowledged, the store makes sure each event is handled once.
+type DedupStore interface {
+	// Claim records the event hash and reports whether it was not recorded before.
+	Claim(ctx context.Context, hash string) (bool, error)
+	// Release forgets the event hash, so the event is handled when it is sent again.
+	Release(ctx context.Context, hash string) error
+}
+
+// Dedup returns middleware that skips events already claimed in store. Events whose handler
+// fails are released so they are handled when HelloSign sends them again.
+func Dedup(store DedupStore) Middleware {
+	return func(next EventHandler) EventHandler {
+		return EventHandlerFunc(func(ctx context.Context, e *Event) error {
+			claimed, err := store.Claim(ctx, e.Hash())
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				return nil
+			}
+			if err := next.HandleEvent(ctx, e); err != nil {
+				if rErr := store.Release(ctx, e.Hash()); rErr != nil {
+					return fmt.Errorf("%v, releasing event: %v", err, rErr)
+				}
+				return err
+			}
+			return nil
+		})
+	}
+}
+
+// MemoryDedupStore keeps event hashes in memory, evicting the least recently claimed hashes
+// beyond its capacity and hashes older than its ttl.
+type MemoryDedupStore struct {
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	order    *list.List // Front is the most recently claimed
+}
+
+type dedupEntry struct {
+	hash      string
+	claimedAt time.Time
+}
+
+// NewMemoryDedupStore creates a store holding up to capacity hashes for ttl. A capacity or ttl
+// of zero means no limit.
+func NewMemoryDedupStore(capacity int, ttl time.Duration) *MemoryDedupStore {
+	return &MemoryDedupStore{
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Claim records the event hash and reports whether it was not recorded before.
+func (s *MemoryDedupStore) Claim(ctx context.Context, hash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.expire(now)
+	if _, ok := s.entries[hash]; ok {
+		return false, nil
+	}
+	s.entries[hash] = s.order.PushFront(&dedupEntry{hash: hash, claimedAt: now})
+	if s.capacity > 0 && s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+	return true, nil
+}
+
+// Release forgets the event hash.
+func (s *MemoryDedupStore) Release(ctx context.Context, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[hash]; ok {
+		s.remove(el)
+	}
+	return nil
+}
+
+func (s *MemoryDedupStore) expire(now time.Time) {
+	if s.ttl <= 0 {
+		return
+	}
+	for el := s.order.Back(); el != nil; el = s.order.Back() {
+		if now.Sub(el.Value.(*dedupEntry).claimedAt) < s.ttl {
+			return
+		}
+		s.remove(el)
+	}
+}
+
+func (s *MemoryDedupStore) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.entries, el.Value.(*dedupEntry).hash)
+}
+
+// compactMinLines is the number of lines the file of a FileDedupStore may grow to before it is compacted.
+const compactMinLines = 64
+
+// FileDedupStore keeps event hashes in an append-only file so they survive restarts. Each line
+// holds a claimed or released hash. Expired hashes are forgotten when hashes are claimed, the file
+// is compacted when it holds more than twice as many lines as live hashes.
+type FileDedupStore struct {
+	path    string
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	f       *os.File
+	lines   int // Lines in the file
+	entries map[string]*list.Element
+	order   *list.List // Front is the most recently claimed
+}
+
+// OpenFileDedupStore opens, or creates, the store at path holding hashes for ttl. A ttl of zero
+// keeps hashes forever.
+func OpenFileDedupStore(path string, ttl time.Duration) (*FileDedupStore, error) {
+	s := &FileDedupStore{
+		path:    path,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	s.expire(s.now())
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load reads the claimed hashes, lines are "+<unix seconds> <hash>" or "-<hash>".
+func (s *FileDedupStore) load() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "+"):
+			parts := strings.SplitN(line[1:], " ", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			secs, err := strconv.ParseInt(parts[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			s.add(parts[1], time.Unix(secs, 0))
+		case strings.HasPrefix(line, "-"):
+			s.remove(line[1:])
+		}
+	}
+	return sc.Err()
+}
+
+// compact rewrites the file with the live hashes, oldest first.
+func (s *FileDedupStore) compact() error {
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for el := s.order.Back(); el != nil; el = el.Prev() {
+		e := el.Value.(*dedupEntry)
+		fmt.Fprintf(w, "+%d %s\n", e.claimedAt.Unix(), e.hash)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	if s.f != nil {
+		s.f.Close()
+	}
+	s.f, err = os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0600)
+	s.lines = s.order.Len()
+	return err
+}
+
+func (s *FileDedupStore) add(hash string, claimedAt time.Time) {
+	s.remove(hash)
+	s.entries[hash] = s.order.PushFront(&dedupEntry{hash: hash, claimedAt: claimedAt})
+}
+
+func (s *FileDedupStore) remove(hash string) {
+	if el, ok := s.entries[hash]; ok {
+		s.order.Remove(el)
+		delete(s.entries, hash)
+	}
+}
+
+// expire forgets the hashes claimed more than ttl ago.
+func (s *FileDedupStore) expire(now time.Time) {
+	if s.ttl <= 0 {
+		return
+	}
+	for el := s.order.Back(); el != nil; el = s.order.Back() {
+		e := el.Value.(*dedupEntry)
+		if now.Sub(e.claimedAt) < s.ttl {
+			return
+		}
+		s.remove(e.hash)
+	}
+}
+
+// Claim records the event hash and reports whether it was not recorded before.
+func (s *FileDedupStore) Claim(ctx context.Context, hash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.expire(now)
+	if _, ok := s.entries[hash]; ok {
+		return false, nil
+	}
+	if s.lines >= compactMinLines && s.lines > 2*s.order.Len() {
+		if err := s.compact(); err != nil {
+			return false, err
+		}
+	}
+	if err := s.append(fmt.Sprintf("+%d %s\n", now.Unix(), hash)); err != nil {
+		return false, err
+	}
+	s.add(hash, now)
+	return true, nil
+}
+
+// Release forgets the event hash.
+func (s *FileDedupStore) Release(ctx context.Context, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[hash]; !ok {
+		return nil
+	}
+	if err := s.append(fmt.Sprintf("-%s\n", hash)); err != nil {
+		return err
+	}
+	s.remove(hash)
+	return nil
+}
+
+func (s *FileDedupStore) append(line string) error {
+	if _, err := s.f.WriteString(line); err != nil {
+		return err
+	}
+	s.lines++
+	return s.f.Sync()
+}
+
+// Close closes the underlying file.
+func (s *FileDedupStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
@@ -0,0 +1,129 @@
+package callback_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/StefanNyman/hellosign/callback"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dedup", func() {
+	var (
+		ctx context.Context
+		dir string
+	)
+
+	_ = BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		dir, err = ioutil.TempDir("", "callback")
+		Expect(err).To(BeNil())
+	})
+
+	_ = AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	claim := func(store callback.DedupStore, hash string) bool {
+		claimed, err := store.Claim(ctx, hash)
+		Expect(err).To(BeNil())
+		return claimed
+	}
+
+	It("claims hashes in memory once", func() {
+		store := callback.NewMemoryDedupStore(0, 0)
+		Expect(claim(store, "a")).To(BeTrue())
+		Expect(claim(store, "a")).To(BeFalse())
+		Expect(store.Release(ctx, "a")).To(Succeed())
+		Expect(claim(store, "a")).To(BeTrue())
+	})
+
+	It("evicts the least recently claimed hashes from memory", func() {
+		store := callback.NewMemoryDedupStore(2, 0)
+		Expect(claim(store, "a")).To(BeTrue())
+		Expect(claim(store, "b")).To(BeTrue())
+		Expect(claim(store, "c")).To(BeTrue())
+		Expect(claim(store, "a")).To(BeTrue())
+		Expect(claim(store, "c")).To(BeFalse())
+	})
+
+	It("expires hashes in memory", func() {
+		store := callback.NewMemoryDedupStore(0, 10*time.Millisecond)
+		Expect(claim(store, "a")).To(BeTrue())
+		time.Sleep(20 * time.Millisecond)
+		Expect(claim(store, "a")).To(BeTrue())
+	})
+
+	It("keeps hashes in a file across restarts", func() {
+		path := filepath.Join(dir, "dedup")
+		store, err := callback.OpenFileDedupStore(path, time.Hour)
+		Expect(err).To(BeNil())
+		Expect(claim(store, "a")).To(BeTrue())
+		Expect(claim(store, "b")).To(BeTrue())
+		Expect(store.Release(ctx, "b")).To(Succeed())
+		Expect(store.Close()).To(Succeed())
+
+		store, err = callback.OpenFileDedupStore(path, time.Hour)
+		Expect(err).To(BeNil())
+		defer store.Close()
+		Expect(claim(store, "a")).To(BeFalse())
+		Expect(claim(store, "b")).To(BeTrue())
+	})
+
+	It("compacts the file and forgets expired hashes while running", func() {
+		path := filepath.Join(dir, "dedup")
+		lines := func() int {
+			b, err := ioutil.ReadFile(path)
+			Expect(err).To(BeNil())
+			return strings.Count(string(b), "\n")
+		}
+		store, err := callback.OpenFileDedupStore(path, 20*time.Millisecond)
+		Expect(err).To(BeNil())
+		defer store.Close()
+
+		for i := 0; i < 500; i++ {
+			Expect(claim(store, "a")).To(BeTrue())
+			Expect(store.Release(ctx, "a")).To(Succeed())
+		}
+		Expect(lines()).To(BeNumerically("<", 100))
+
+		for i := 0; i < 100; i++ {
+			Expect(claim(store, fmt.Sprintf("hash-%d", i))).To(BeTrue())
+		}
+		time.Sleep(40 * time.Millisecond)
+		Expect(claim(store, "hash-0")).To(BeTrue())
+		Expect(lines()).To(Equal(1))
+	})
+
+	It("handles redelivered events once", func() {
+		handled := 0
+		fail := true
+		handler := callback.NewHandler(callback.NewVerifier(apiKey))
+		handler.Use(callback.Dedup(callback.NewMemoryDedupStore(100, time.Hour)))
+		handler.HandleFunc(callback.SignatureRequestAllSigned, func(ctx context.Context, e *callback.Event) error {
+			if fail {
+				fail = false
+				return errors.New("failed")
+			}
+			handled++
+			return nil
+		})
+		payload := signedPayload(time.Now(), callback.SignatureRequestAllSigned)
+		codes := []int{}
+		for i := 0; i < 3; i++ {
+			rec := serveHandler(handler, newCallbackRequest(payload))
+			codes = append(codes, rec.Code)
+		}
+		Expect(codes).To(Equal([]int{http.StatusInternalServerError, http.StatusOK, http.StatusOK}))
+		Expect(handled).To(Equal(1))
+	})
+})
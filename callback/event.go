@@ -0,0 +1,223 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package callback implements receiving of HelloSign callback events.
+
+HelloSign posts events to the callback urls of accounts and api apps as multipart forms with the
+event in the json field. Events are signed with the api key of the account, use a Verifier to parse
+requests and reject events that were not sent by HelloSign.
+
+Handler is an http.Handler that verifies events, dispatches them to handlers registered per event
+type and replies with the acknowledgement HelloSign expects:
+
+	h := callback.NewHandler(callback.NewVerifier(apiKey))
+	h.HandleFunc(callback.SignatureRequestAllSigned, func(ctx context.Context, e *callback.Event) error {
+		return archive(ctx, e.SignatureRequest)
+	})
+	http.Handle("/hellosign/callback", h)
+
+Slow handlers risk timeouts and resent events. With a Queue the Handler stores events in a journal
+on disk and acknowledges them immediately, the queue then handles them in the background:
+
+	q, err := callback.OpenQueue("/var/lib/app/callbacks", callback.WithWorkers(4))
+	h := callback.NewHandler(callback.NewVerifier(apiKey), callback.WithQueue(q))
+	go q.Run(ctx, h)
+*/
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+)
+
+const jsonField = "json"
+
+// Event types sent by HelloSign.
+const (
+	SignatureRequestViewed         = "signature_request_viewed"
+	SignatureRequestSigned         = "signature_request_signed"
+	SignatureRequestDownloadable   = "signature_request_downloadable"
+	SignatureRequestSent           = "signature_request_sent"
+	SignatureRequestDeclined       = "signature_request_declined"
+	SignatureRequestReassigned     = "signature_request_reassigned"
+	SignatureRequestRemind         = "signature_request_remind"
+	SignatureRequestAllSigned      = "signature_request_all_signed"
+	SignatureRequestEmailBounce    = "signature_request_email_bounce"
+	SignatureRequestInvalid        = "signature_request_invalid"
+	SignatureRequestCanceled       = "signature_request_canceled"
+	SignatureRequestPrepared       = "signature_request_prepared"
+	FileError                      = "file_error"
+	UnknownError                   = "unknown_error"
+	SignURLInvalid                 = "sign_url_invalid"
+	AccountConfirmed               = "account_confirmed"
+	TemplateCreated                = "template_created"
+	TemplateError                  = "template_error"
+	CallbackTest                   = "callback_test"
+	SignatureRequestExpired        = "signature_request_expired"
+	SignatureRequestSignerRemoved  = "signature_request_signer_removed"
+	UnclaimedDraftCreated          = "unclaimed_draft_created"
+	UnclaimedDraftDeleted          = "unclaimed_draft_deleted"
+	BulkSendJobSignatureRequestErr = "bulk_send_job_signature_request_error"
+)
+
+// Errors returned when events can not be accepted.
+var (
+	ErrMalformedEvent = errors.New("callback: malformed event")
+	ErrInvalidHash    = errors.New("callback: invalid event hash")
+	ErrStaleEvent     = errors.New("callback: stale event")
+)
+
+// Event is an event sent to a callback url. Depending on the event type the signature request,
+// template or account the event is about is included.
+type Event struct {
+	Event            EventInfo         `json:"event"`
+	SignatureRequest *hellosign.SigReq `json:"signature_request,omitempty"`
+	Template         *hellosign.Tpl    `json:"template,omitempty"`
+	Account          *hellosign.Acc    `json:"account,omitempty"`
+}
+
+// EventInfo describes what happened.
+type EventInfo struct {
+	Time     string        `json:"event_time"` // Seconds since epoch
+	Type     string        `json:"event_type"`
+	Hash     string        `json:"event_hash"`
+	Metadata EventMetadata `json:"event_metadata"`
+}
+
+// EventMetadata additional information about an event.
+type EventMetadata struct {
+	RelatedSignatureID   *string `json:"related_signature_id"`
+	ReportedForAccountID *string `json:"reported_for_account_id"`
+	ReportedForAppID     *string `json:"reported_for_app_id"`
+	EventMessage         *string `json:"event_message"`
+}
+
+// Type returns the event type, such as SignatureRequestSigned.
+func (e *Event) Type() string {
+	return e.Event.Type
+}
+
+// Hash returns the event hash, which is unique for each event.
+func (e *Event) Hash() string {
+	return e.Event.Hash
+}
+
+// Time returns when the event occurred.
+func (e *Event) Time() (time.Time, error) {
+	secs, err := strconv.ParseInt(e.Event.Time, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrMalformedEvent
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// ParseEvent parses the json payload of an event without verifying it.
+func ParseEvent(payload []byte) (*Event, error) {
+	e := &Event{}
+	if err := json.Unmarshal(payload, e); err != nil {
+		return nil, ErrMalformedEvent
+	}
+	if e.Event.Type == "" || e.Event.Time == "" || e.Event.Hash == "" {
+		return nil, ErrMalformedEvent
+	}
+	return e, nil
+}
+
+// ParseRequest parses the event posted in a callback request without verifying it.
+func ParseRequest(r *http.Request) (*Event, error) {
+	payload := r.FormValue(jsonField)
+	if payload == "" {
+		return nil, ErrMalformedEvent
+	}
+	return ParseEvent([]byte(payload))
+}
+
+// Verifier checks that events were sent by HelloSign.
+type Verifier struct {
+	apiKey string
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// VerifierOption configures a Verifier.
+type VerifierOption func(*Verifier)
+
+// DefaultMaxAge is how far the time of an event may be from now for a Verifier to accept it, unless
+// set with WithMaxAge. It limits how long a captured event can be replayed.
+const DefaultMaxAge = 5 * time.Minute
+
+// WithMaxAge rejects events that occurred longer than maxAge ago, or further than maxAge in the future.
+// A maxAge of 0 disables the check, for example to accept events resent long after they occurred.
+func WithMaxAge(maxAge time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.maxAge = maxAge
+	}
+}
+
+// NewVerifier creates a verifier for events signed with the api key of an account.
+func NewVerifier(apiKey string, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		apiKey: apiKey,
+		maxAge: DefaultMaxAge,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify checks the event hash, an HMAC-SHA256 of the event time and type keyed by the api key,
+// and the event time.
+func (v *Verifier) Verify(e *Event) error {
+	expected := EventHash(v.apiKey, e.Event.Time, e.Event.Type)
+	got, err := hex.DecodeString(e.Event.Hash)
+	if err != nil {
+		return ErrInvalidHash
+	}
+	want, _ := hex.DecodeString(expected)
+	if !hmac.Equal(got, want) {
+		return ErrInvalidHash
+	}
+	if v.maxAge <= 0 {
+		return nil
+	}
+	t, err := e.Time()
+	if err != nil {
+		return err
+	}
+	age := v.now().Sub(t)
+	if age > v.maxAge || -age > v.maxAge {
+		return ErrStaleEvent
+	}
+	return nil
+}
+
+// ParseRequest parses and verifies the event posted in a callback request.
+func (v *Verifier) ParseRequest(r *http.Request) (*Event, error) {
+	e, err := ParseRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.Verify(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// EventHash returns the hash HelloSign computes for an event.
+func EventHash(apiKey, eventTime, eventType string) string {
+	mac := hmac.New(sha256.New, []byte(apiKey))
+	mac.Write([]byte(eventTime + eventType))
+	return hex.EncodeToString(mac.Sum(nil))
+}
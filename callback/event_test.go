@@ -0,0 +1,107 @@
+package callback_test
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/StefanNyman/hellosign/callback"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const apiKey = "asdf"
+
+func eventPayload(eventTime, eventType, eventHash string) string {
+	return fmt.Sprintf(`{
+		"event": {
+			"event_time": "%s",
+			"event_type": "%s",
+			"event_hash": "%s",
+			"event_metadata": {
+				"related_signature_id": "ad4d8a769b555fa5ef38691465d426682bf2c992",
+				"reported_for_account_id": "63522885f9261e2b04eea043933ee7313eb674fd",
+				"reported_for_app_id": null,
+				"event_message": null
+			}
+		},
+		"signature_request": {
+			"signature_request_id": "fa5c8a0b0f492d768749333ad6fcc214c111e967",
+			"title": "Contract",
+			"is_complete": true
+		}
+	}`, eventTime, eventType, eventHash)
+}
+
+func signedPayload(t time.Time, eventType string) string {
+	eventTime := strconv.FormatInt(t.Unix(), 10)
+	return eventPayload(eventTime, eventType, callback.EventHash(apiKey, eventTime, eventType))
+}
+
+var _ = Describe("Event", func() {
+	It("parses events from callback requests", func() {
+		e, err := callback.ParseRequest(newCallbackRequest(eventPayload("1348177752", callback.SignatureRequestAllSigned, "abc")))
+		Expect(err).To(BeNil())
+		Expect(e.Type()).To(Equal(callback.SignatureRequestAllSigned))
+		Expect(e.Hash()).To(Equal("abc"))
+		t, err := e.Time()
+		Expect(err).To(BeNil())
+		Expect(t).To(Equal(time.Unix(1348177752, 0)))
+		Expect(*e.Event.Metadata.RelatedSignatureID).To(Equal("ad4d8a769b555fa5ef38691465d426682bf2c992"))
+		Expect(e.Event.Metadata.ReportedForAppID).To(BeNil())
+		Expect(e.SignatureRequest).ToNot(BeNil())
+		Expect(e.SignatureRequest.Title).To(Equal("Contract"))
+		Expect(e.SignatureRequest.IsComplete).To(BeTrue())
+		Expect(e.Template).To(BeNil())
+	})
+
+	It("rejects malformed events", func() {
+		_, err := callback.ParseRequest(newCallbackRequest(`{"event": {}}`))
+		Expect(err).To(Equal(callback.ErrMalformedEvent))
+		_, err = callback.ParseRequest(newCallbackRequest(`not json`))
+		Expect(err).To(Equal(callback.ErrMalformedEvent))
+	})
+
+	It("verifies event hashes", func() {
+		v := callback.NewVerifier(apiKey)
+		e, err := v.ParseRequest(newCallbackRequest(signedPayload(time.Now(), callback.CallbackTest)))
+		Expect(err).To(BeNil())
+		Expect(e.Type()).To(Equal(callback.CallbackTest))
+	})
+
+	It("rejects forged events", func() {
+		v := callback.NewVerifier(apiKey)
+		eventTime := strconv.FormatInt(time.Now().Unix(), 10)
+		forged := eventPayload(eventTime, callback.SignatureRequestAllSigned,
+			callback.EventHash("other key", eventTime, callback.SignatureRequestAllSigned))
+		_, err := v.ParseRequest(newCallbackRequest(forged))
+		Expect(err).To(Equal(callback.ErrInvalidHash))
+		tampered := eventPayload(eventTime, callback.SignatureRequestAllSigned,
+			callback.EventHash(apiKey, eventTime, callback.SignatureRequestDeclined))
+		_, err = v.ParseRequest(newCallbackRequest(tampered))
+		Expect(err).To(Equal(callback.ErrInvalidHash))
+	})
+
+	It("rejects stale events", func() {
+		v := callback.NewVerifier(apiKey, callback.WithMaxAge(time.Hour))
+		_, err := v.ParseRequest(newCallbackRequest(signedPayload(time.Now().Add(-2*time.Hour), callback.CallbackTest)))
+		Expect(err).To(Equal(callback.ErrStaleEvent))
+		_, err = v.ParseRequest(newCallbackRequest(signedPayload(time.Now().Add(-time.Minute), callback.CallbackTest)))
+		Expect(err).To(BeNil())
+	})
+
+	It("rejects stale events by default", func() {
+		v := callback.NewVerifier(apiKey)
+		stale := time.Now().Add(-callback.DefaultMaxAge - time.Minute)
+		_, err := v.ParseRequest(newCallbackRequest(signedPayload(stale, callback.CallbackTest)))
+		Expect(err).To(Equal(callback.ErrStaleEvent))
+		_, err = v.ParseRequest(newCallbackRequest(signedPayload(time.Now().Add(callback.DefaultMaxAge+time.Minute), callback.CallbackTest)))
+		Expect(err).To(Equal(callback.ErrStaleEvent))
+		_, err = v.ParseRequest(newCallbackRequest(signedPayload(time.Now().Add(-time.Minute), callback.CallbackTest)))
+		Expect(err).To(BeNil())
+
+		v = callback.NewVerifier(apiKey, callback.WithMaxAge(0))
+		_, err = v.ParseRequest(newCallbackRequest(signedPayload(stale, callback.CallbackTest)))
+		Expect(err).To(BeNil())
+	})
+})
@@ -0,0 +1,169 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package callback
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// AckBody is the response body HelloSign expects to acknowledge an event. Events that are not
+// acknowledged are sent again.
+const AckBody = "Hello API Event Received"
+
+// EventHandler handles verified events.
+type EventHandler interface {
+	HandleEvent(ctx context.Context, e *Event) error
+}
+
+// EventHandlerFunc adapts a function to an EventHandler.
+type EventHandlerFunc func(ctx context.Context, e *Event) error
+
+// HandleEvent calls f(ctx, e).
+func (f EventHandlerFunc) HandleEvent(ctx context.Context, e *Event) error {
+	return f(ctx, e)
+}
+
+// Middleware wraps an EventHandler, for example to log or filter events.
+type Middleware func(EventHandler) EventHandler
+
+// Handler is an http.Handler that verifies callback events and dispatches them to the handler
+// registered for their event type. Events are acknowledged when the handler succeeds or when
+// no handler is registered for the type. Failing or panicking handlers get the event resent.
+type Handler struct {
+	verifier   *Verifier
+	onError    func(r *http.Request, err error)
+	mu         sync.RWMutex
+	handlers   map[string]EventHandler
+	fallback   EventHandler
+	middleware []Middleware
+	queue      *Queue
+}
+
+// HandlerOption configures a Handler.
+type HandlerOption func(*Handler)
+
+// WithErrorHandler sets a function called with events that could not be verified and errors
+// returned by, or panics recovered from, event handlers.
+func WithErrorHandler(onError func(r *http.Request, err error)) HandlerOption {
+	return func(h *Handler) {
+		h.onError = onError
+	}
+}
+
+// NewHandler creates a Handler accepting events that pass verifier.
+func NewHandler(verifier *Verifier, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		verifier: verifier,
+		onError:  func(*http.Request, error) {},
+		handlers: map[string]EventHandler{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Handle registers the handler for an event type, such as SignatureRequestSigned.
+func (h *Handler) Handle(eventType string, handler EventHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = handler
+}
+
+// HandleFunc registers the handler function for an event type.
+func (h *Handler) HandleFunc(eventType string, handler func(ctx context.Context, e *Event) error) {
+	h.Handle(eventType, EventHandlerFunc(handler))
+}
+
+// Fallback registers the handler for event types without a registered handler.
+func (h *Handler) Fallback(handler EventHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fallback = handler
+}
+
+// Use adds middleware wrapping all event handlers. Middleware added first is called first.
+func (h *Handler) Use(middleware ...Middleware) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.middleware = append(h.middleware, middleware...)
+}
+
+func (h *Handler) handler(eventType string) EventHandler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	handler, ok := h.handlers[eventType]
+	if !ok {
+		handler = h.fallback
+	}
+	if handler == nil {
+		return nil
+	}
+	for i := len(h.middleware) - 1; i >= 0; i-- {
+		handler = h.middleware[i](handler)
+	}
+	return handler
+}
+
+// ServeHTTP verifies the posted event and dispatches it, or stores it when the handler has a queue.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	e, err := h.verifier.ParseRequest(r)
+	if err != nil {
+		h.onError(r, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.queue != nil {
+		err = h.queue.HandleEvent(r.Context(), e)
+	} else {
+		err = h.dispatch(r.Context(), e)
+	}
+	if err != nil {
+		h.onError(r, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	Ack(w)
+}
+
+// HandleEvent dispatches the event to the handler registered for its type, so a Queue can run
+// with the Handler.
+func (h *Handler) HandleEvent(ctx context.Context, e *Event) error {
+	return h.dispatch(ctx, e)
+}
+
+// dispatch calls the handler for the event, turning panics into errors.
+func (h *Handler) dispatch(ctx context.Context, e *Event) error {
+	handler := h.handler(e.Type())
+	if handler == nil {
+		return nil
+	}
+	return dispatchSafely(ctx, handler, e)
+}
+
+// dispatchSafely calls the handler, turning panics into errors.
+func dispatchSafely(ctx context.Context, handler EventHandler, e *Event) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("callback: panic handling %s event: %v", e.Type(), p)
+		}
+	}()
+	return handler.HandleEvent(ctx, e)
+}
+
+// Ack writes the response acknowledging an event.
+func Ack(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, AckBody)
+}
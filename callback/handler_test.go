@@ -0,0 +1,118 @@
+package callback_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/StefanNyman/hellosign/callback"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handler", func() {
+	var (
+		handler *callback.Handler
+		errs    []error
+	)
+
+	_ = BeforeEach(func() {
+		errs = nil
+		handler = callback.NewHandler(callback.NewVerifier(apiKey),
+			callback.WithErrorHandler(func(r *http.Request, err error) {
+				errs = append(errs, err)
+			}))
+	})
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		return serveHandler(handler, req)
+	}
+
+	It("dispatches events by type and acknowledges them", func() {
+		signed, allSigned := 0, 0
+		handler.HandleFunc(callback.SignatureRequestSigned, func(ctx context.Context, e *callback.Event) error {
+			signed++
+			return nil
+		})
+		handler.HandleFunc(callback.SignatureRequestAllSigned, func(ctx context.Context, e *callback.Event) error {
+			allSigned++
+			Expect(e.SignatureRequest.Title).To(Equal("Contract"))
+			return nil
+		})
+		rec := serve(newCallbackRequest(signedPayload(time.Now(), callback.SignatureRequestAllSigned)))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(callback.AckBody))
+		Expect(signed).To(Equal(0))
+		Expect(allSigned).To(Equal(1))
+	})
+
+	It("acknowledges unknown event types", func() {
+		rec := serve(newCallbackRequest(signedPayload(time.Now(), "some_new_event")))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(callback.AckBody))
+	})
+
+	It("dispatches unknown event types to the fallback", func() {
+		var eventType string
+		handler.Fallback(callback.EventHandlerFunc(func(ctx context.Context, e *callback.Event) error {
+			eventType = e.Type()
+			return nil
+		}))
+		rec := serve(newCallbackRequest(signedPayload(time.Now(), "some_new_event")))
+		Expect(rec.Body.String()).To(Equal(callback.AckBody))
+		Expect(eventType).To(Equal("some_new_event"))
+	})
+
+	It("rejects forged events", func() {
+		rec := serve(newCallbackRequest(eventPayload("1348177752", callback.CallbackTest, "abc")))
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(rec.Body.String()).ToNot(ContainSubstring(callback.AckBody))
+		Expect(errs).To(Equal([]error{callback.ErrInvalidHash}))
+	})
+
+	It("does not acknowledge events when the handler fails", func() {
+		handlerErr := errors.New("database unavailable")
+		handler.HandleFunc(callback.CallbackTest, func(ctx context.Context, e *callback.Event) error {
+			return handlerErr
+		})
+		rec := serve(newCallbackRequest(signedPayload(time.Now(), callback.CallbackTest)))
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(errs).To(Equal([]error{handlerErr}))
+	})
+
+	It("recovers from panicking handlers", func() {
+		handler.HandleFunc(callback.CallbackTest, func(ctx context.Context, e *callback.Event) error {
+			panic("boom")
+		})
+		rec := serve(newCallbackRequest(signedPayload(time.Now(), callback.CallbackTest)))
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Error()).To(ContainSubstring("boom"))
+	})
+
+	It("wraps handlers in middleware in order", func() {
+		calls := []string{}
+		mw := func(name string) callback.Middleware {
+			return func(next callback.EventHandler) callback.EventHandler {
+				return callback.EventHandlerFunc(func(ctx context.Context, e *callback.Event) error {
+					calls = append(calls, name)
+					return next.HandleEvent(ctx, e)
+				})
+			}
+		}
+		handler.Use(mw("first"), mw("second"))
+		handler.HandleFunc(callback.CallbackTest, func(ctx context.Context, e *callback.Event) error {
+			calls = append(calls, "handler")
+			return nil
+		})
+		serve(newCallbackRequest(signedPayload(time.Now(), callback.CallbackTest)))
+		Expect(calls).To(Equal([]string{"first", "second", "handler"}))
+	})
+
+	It("only accepts posts", func() {
+		rec := serve(httptest.NewRequest(http.MethodGet, "/hellosign/callback", nil))
+		Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
@@ -0,0 +1,458 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package callback
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	journalFile    = "journal.jsonl"
+	acksFile       = "acks"
+	deadLetterFile = "dead.jsonl"
+)
+
+// compactAcks is the number of acknowledged events after which the journal is rewritten with only
+// the events not handled yet.
+const compactAcks = 128
+
+// QueuedEvent is an event stored in the journal of a Queue.
+type QueuedEvent struct {
+	ID         uint64    `json:"id"`
+	ReceivedAt time.Time `json:"received_at"`
+	Event      *Event    `json:"event"`
+	Attempts   int       `json:"attempts,omitempty"` // Set in the dead letter file
+	LastErr    string    `json:"last_error,omitempty"`
+}
+
+// Queue persists events to an append-only journal in a directory and handles them asynchronously
+// with a pool of workers. Failed events are retried with backoff and moved to a dead letter file
+// once all attempts failed. Events not handled when the process stops are handled on the next Run.
+// Handled events are removed from the journal as the queue runs. A directory must only be used by one Queue at a time.
+type Queue struct {
+	dir         string
+	workers     int
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+
+	mu       sync.Mutex
+	journal  *os.File
+	acks     *os.File
+	acked    int // Lines in the acks file
+	nextID   uint64
+	pending  []*QueuedEvent
+	inFlight map[uint64]*QueuedEvent // Events being handled by workers
+	notify   chan struct{}
+}
+
+// QueueOption configures a Queue.
+type QueueOption func(*Queue)
+
+// WithWorkers sets the number of events handled concurrently. Defaults to 1.
+func WithWorkers(n int) QueueOption {
+	return func(q *Queue) {
+		q.workers = n
+	}
+}
+
+// WithMaxAttempts sets how many times an event is handled before it is moved to the dead letter
+// file. Defaults to 5.
+func WithMaxAttempts(n int) QueueOption {
+	return func(q *Queue) {
+		q.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the wait after the first failed attempt, doubled after each further attempt up to max.
+// Defaults to one second and one minute.
+func WithBackoff(min, max time.Duration) QueueOption {
+	return func(q *Queue) {
+		q.minBackoff = min
+		q.maxBackoff = max
+	}
+}
+
+// WithQueue makes the handler acknowledge verified events as soon as they are stored in the
+// queue. The events are dispatched when the queue runs with the handler.
+func WithQueue(q *Queue) HandlerOption {
+	return func(h *Handler) {
+		h.queue = q
+	}
+}
+
+// OpenQueue opens, or creates, the queue stored in dir. Handled events are removed from the journal.
+func OpenQueue(dir string, opts ...QueueOption) (*Queue, error) {
+	q := &Queue{
+		dir:         dir,
+		workers:     1,
+		maxAttempts: 5,
+		minBackoff:  time.Second,
+		maxBackoff:  time.Minute,
+		inFlight:    map[uint64]*QueuedEvent{},
+		notify:      make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// load reads the events that have not been handled and rewrites the journal with only those.
+func (q *Queue) load() error {
+	acked := map[uint64]bool{}
+	if err := readLines(filepath.Join(q.dir, acksFile), func(line []byte) error {
+		id, err := strconv.ParseUint(string(line), 10, 64)
+		if err == nil {
+			acked[id] = true
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := readLines(filepath.Join(q.dir, journalFile), func(line []byte) error {
+		qe := &QueuedEvent{}
+		if err := json.Unmarshal(line, qe); err != nil {
+			// A partially written last line, the event was never acknowledged to HelloSign.
+			return nil
+		}
+		if qe.ID >= q.nextID {
+			q.nextID = qe.ID + 1
+		}
+		if !acked[qe.ID] {
+			q.pending = append(q.pending, qe)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := writeLines(filepath.Join(q.dir, journalFile), q.pending); err != nil {
+		return err
+	}
+	var err error
+	if q.journal, err = os.OpenFile(filepath.Join(q.dir, journalFile), os.O_APPEND|os.O_WRONLY, 0600); err != nil {
+		return err
+	}
+	q.acks, err = os.OpenFile(filepath.Join(q.dir, acksFile), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	return err
+}
+
+// HandleEvent stores the event in the journal, it returns once the event is on disk.
+func (q *Queue) HandleEvent(ctx context.Context, e *Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.enqueue(e)
+}
+
+// enqueue appends the event to the journal and the pending events. q.mu must be held.
+func (q *Queue) enqueue(e *Event) error {
+	qe := &QueuedEvent{ID: q.nextID, ReceivedAt: time.Now(), Event: e}
+	if err := appendJSON(q.journal, qe); err != nil {
+		return err
+	}
+	q.nextID++
+	q.pending = append(q.pending, qe)
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run handles queued events with handler until ctx is done. Events being handled when ctx is done
+// stay in the journal. When the queue files can not be written, all workers stop and Run returns
+// the error.
+func (q *Queue) Run(ctx context.Context, handler EventHandler) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		runErr  error
+	)
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := q.work(runCtx, handler); err != nil {
+				errOnce.Do(func() {
+					runErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if runErr != nil {
+		return runErr
+	}
+	return ctx.Err()
+}
+
+// work handles events until ctx is done or an event can not be acknowledged or dead lettered.
+func (q *Queue) work(ctx context.Context, handler EventHandler) error {
+	for ctx.Err() == nil {
+		qe := q.next()
+		if qe == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-q.notify:
+				continue
+			}
+		}
+		if err := q.process(ctx, handler, qe); err != nil {
+			// Keep the event for the next run.
+			q.mu.Lock()
+			delete(q.inFlight, qe.ID)
+			q.pending = append([]*QueuedEvent{qe}, q.pending...)
+			q.mu.Unlock()
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *Queue) next() *QueuedEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return nil
+	}
+	qe := q.pending[0]
+	q.pending = q.pending[1:]
+	q.inFlight[qe.ID] = qe
+	if len(q.pending) > 0 {
+		// Wake up another worker for the remaining events.
+		select {
+		case q.notify <- struct{}{}:
+		default:
+		}
+	}
+	return qe
+}
+
+// process handles the event, retrying with backoff, and acknowledges or dead letters it. It only
+// fails when ctx is done or the queue files can not be written.
+func (q *Queue) process(ctx context.Context, handler EventHandler, qe *QueuedEvent) error {
+	var err error
+	backoff := q.minBackoff
+	for attempt := 1; attempt <= q.maxAttempts; attempt++ {
+		if attempt > 1 {
+			if sErr := sleep(ctx, backoff); sErr != nil {
+				return sErr
+			}
+			if backoff *= 2; backoff > q.maxBackoff {
+				backoff = q.maxBackoff
+			}
+		}
+		if err = dispatchSafely(ctx, handler, qe.Event); err == nil {
+			return q.ack(qe.ID)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	dead := *qe
+	dead.Attempts = q.maxAttempts
+	dead.LastErr = err.Error()
+	q.mu.Lock()
+	f, fErr := os.OpenFile(filepath.Join(q.dir, deadLetterFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if fErr == nil {
+		fErr = appendJSON(f, &dead)
+		if cErr := f.Close(); fErr == nil {
+			fErr = cErr
+		}
+	}
+	q.mu.Unlock()
+	if fErr != nil {
+		return fErr
+	}
+	return q.ack(qe.ID)
+}
+
+func (q *Queue) ack(id uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, err := fmt.Fprintf(q.acks, "%d\n", id); err != nil {
+		return err
+	}
+	if err := q.acks.Sync(); err != nil {
+		return err
+	}
+	delete(q.inFlight, id)
+	if q.acked++; q.acked >= compactAcks {
+		return q.compact()
+	}
+	return nil
+}
+
+// compact rewrites the journal with the pending and in flight events and truncates the acks file.
+// The journal is replaced first, acks of events no longer in the journal are ignored when loading.
+// q.mu must be held.
+func (q *Queue) compact() error {
+	live := make([]*QueuedEvent, 0, len(q.pending)+len(q.inFlight))
+	for _, qe := range q.inFlight {
+		live = append(live, qe)
+	}
+	live = append(live, q.pending...)
+	sort.Slice(live, func(i, j int) bool { return live[i].ID < live[j].ID })
+	path := filepath.Join(q.dir, journalFile)
+	if err := writeLines(path, live); err != nil {
+		return err
+	}
+	journal, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	q.journal.Close()
+	q.journal = journal
+	if err := q.acks.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := q.acks.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	q.acked = 0
+	return nil
+}
+
+// DeadLetters returns the events in the dead letter file.
+func (q *Queue) DeadLetters() ([]*QueuedEvent, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.readDeadLetters()
+}
+
+func (q *Queue) readDeadLetters() ([]*QueuedEvent, error) {
+	dead := []*QueuedEvent{}
+	err := readLines(filepath.Join(q.dir, deadLetterFile), func(line []byte) error {
+		qe := &QueuedEvent{}
+		if err := json.Unmarshal(line, qe); err != nil {
+			return err
+		}
+		dead = append(dead, qe)
+		return nil
+	})
+	return dead, err
+}
+
+// Replay moves the events in the dead letter file back into the queue and returns how many were moved.
+// When an event can not be stored, the events not moved are kept in the dead letter file.
+func (q *Queue) Replay(ctx context.Context) (int, error) {
+	// q.mu is held throughout, so workers can not dead letter events while the file is replaced.
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	dead, err := q.readDeadLetters()
+	if err != nil {
+		return 0, err
+	}
+	path := filepath.Join(q.dir, deadLetterFile)
+	for i, qe := range dead {
+		if err := q.enqueue(qe.Event); err != nil {
+			if wErr := writeLines(path, dead[i:]); wErr != nil {
+				return i, wErr
+			}
+			return i, err
+		}
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return len(dead), err
+	}
+	return len(dead), nil
+}
+
+// Close closes the queue files. Run must have returned.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	err := q.journal.Close()
+	if aErr := q.acks.Close(); err == nil {
+		err = aErr
+	}
+	return err
+}
+
+func appendJSON(f *os.File, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func readLines(path string, fn func(line []byte) error) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		if err := fn(sc.Bytes()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// writeLines atomically replaces the file at path with the events.
+func writeLines(path string, events []*QueuedEvent) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	for _, qe := range events {
+		if err := appendJSON(f, qe); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,253 @@
+package callback_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/StefanNyman/hellosign/callback"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Queue", func() {
+	var (
+		dir     string
+		handled chan *callback.Event
+	)
+
+	_ = BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "callback")
+		Expect(err).To(BeNil())
+		handled = make(chan *callback.Event, 10)
+	})
+
+	_ = AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	openQueue := func(opts ...callback.QueueOption) *callback.Queue {
+		q, err := callback.OpenQueue(dir, append([]callback.QueueOption{
+			callback.WithBackoff(time.Millisecond, 5*time.Millisecond),
+		}, opts...)...)
+		Expect(err).To(BeNil())
+		return q
+	}
+
+	// run runs the queue in the background and returns a function stopping it.
+	run := func(q *callback.Queue, handler callback.EventHandler) func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.Run(ctx, handler)
+		}()
+		return func() {
+			cancel()
+			wg.Wait()
+		}
+	}
+
+	record := callback.EventHandlerFunc(func(ctx context.Context, e *callback.Event) error {
+		handled <- e
+		return nil
+	})
+
+	It("acknowledges stored events and handles them asynchronously", func() {
+		q := openQueue(callback.WithWorkers(2))
+		defer q.Close()
+		handler := callback.NewHandler(callback.NewVerifier(apiKey), callback.WithQueue(q))
+		handler.Handle(callback.SignatureRequestAllSigned, record)
+
+		rec := serveHandler(handler, newCallbackRequest(signedPayload(time.Now(), callback.SignatureRequestAllSigned)))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(callback.AckBody))
+		Expect(handled).To(BeEmpty())
+
+		stop := run(q, handler)
+		defer stop()
+		var e *callback.Event
+		Eventually(handled).Should(Receive(&e))
+		Expect(e.Type()).To(Equal(callback.SignatureRequestAllSigned))
+		Expect(e.SignatureRequest.Title).To(Equal("Contract"))
+	})
+
+	It("retries failing handlers", func() {
+		q := openQueue()
+		defer q.Close()
+		attempts := 0
+		stop := run(q, callback.EventHandlerFunc(func(ctx context.Context, e *callback.Event) error {
+			if attempts++; attempts < 3 {
+				return errors.New("boom")
+			}
+			handled <- e
+			return nil
+		}))
+		defer stop()
+		e, err := callback.ParseEvent([]byte(signedPayload(time.Now(), callback.SignatureRequestSigned)))
+		Expect(err).To(BeNil())
+		Expect(q.HandleEvent(context.Background(), e)).To(Succeed())
+		Eventually(handled).Should(Receive())
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("moves poison events to the dead letter file and replays them", func() {
+		q := openQueue(callback.WithMaxAttempts(2))
+		defer q.Close()
+		e, err := callback.ParseEvent([]byte(signedPayload(time.Now(), callback.SignatureRequestSigned)))
+		Expect(err).To(BeNil())
+		Expect(q.HandleEvent(context.Background(), e)).To(Succeed())
+
+		stop := run(q, callback.EventHandlerFunc(func(ctx context.Context, e *callback.Event) error {
+			panic("poison")
+		}))
+		var dead []*callback.QueuedEvent
+		Eventually(func() int {
+			dead, err = q.DeadLetters()
+			Expect(err).To(BeNil())
+			return len(dead)
+		}).Should(Equal(1))
+		stop()
+		Expect(dead[0].Attempts).To(Equal(2))
+		Expect(dead[0].LastErr).To(ContainSubstring("poison"))
+		Expect(dead[0].Event.Hash()).To(Equal(e.Hash()))
+
+		n, err := q.Replay(context.Background())
+		Expect(err).To(BeNil())
+		Expect(n).To(Equal(1))
+		dead, err = q.DeadLetters()
+		Expect(err).To(BeNil())
+		Expect(dead).To(BeEmpty())
+
+		stop = run(q, record)
+		defer stop()
+		Eventually(handled).Should(Receive())
+	})
+
+	It("keeps dead letters that could not be replayed", func() {
+		q := openQueue(callback.WithMaxAttempts(1))
+		for _, eventType := range []string{callback.SignatureRequestSigned, callback.SignatureRequestAllSigned} {
+			e, err := callback.ParseEvent([]byte(signedPayload(time.Now(), eventType)))
+			Expect(err).To(BeNil())
+			Expect(q.HandleEvent(context.Background(), e)).To(Succeed())
+		}
+		stop := run(q, callback.EventHandlerFunc(func(ctx context.Context, e *callback.Event) error {
+			return errors.New("poison")
+		}))
+		Eventually(func() int {
+			dead, err := q.DeadLetters()
+			Expect(err).To(BeNil())
+			return len(dead)
+		}).Should(Equal(2))
+		stop()
+
+		// The journal is closed, so no event can be stored.
+		Expect(q.Close()).To(Succeed())
+		n, err := q.Replay(context.Background())
+		Expect(err).ToNot(BeNil())
+		Expect(n).To(Equal(0))
+		dead, err := q.DeadLetters()
+		Expect(err).To(BeNil())
+		Expect(dead).To(HaveLen(2))
+
+		q = openQueue()
+		defer q.Close()
+		n, err = q.Replay(context.Background())
+		Expect(err).To(BeNil())
+		Expect(n).To(Equal(2))
+	})
+
+	It("stops with an error when the queue files can not be written", func() {
+		runErr := func(q *callback.Queue, handler callback.EventHandler) chan error {
+			errc := make(chan error, 1)
+			go func() {
+				errc <- q.Run(context.Background(), handler)
+			}()
+			return errc
+		}
+		e, err := callback.ParseEvent([]byte(signedPayload(time.Now(), callback.SignatureRequestSigned)))
+		Expect(err).To(BeNil())
+
+		// Handled events can not be acknowledged in a closed journal.
+		q := openQueue(callback.WithWorkers(2))
+		Expect(q.HandleEvent(context.Background(), e)).To(Succeed())
+		Expect(q.Close()).To(Succeed())
+		var rErr error
+		Eventually(runErr(q, record)).Should(Receive(&rErr))
+		Expect(rErr).ToNot(BeNil())
+
+		// The event is still in the journal. Failed events can not be dead lettered when the dead
+		// letter file is a directory.
+		Expect(os.Mkdir(filepath.Join(dir, "dead.jsonl"), 0700)).To(Succeed())
+		q = openQueue(callback.WithWorkers(2), callback.WithMaxAttempts(1))
+		defer q.Close()
+		Eventually(runErr(q, callback.EventHandlerFunc(func(ctx context.Context, e *callback.Event) error {
+			return errors.New("poison")
+		}))).Should(Receive(&rErr))
+		Expect(rErr).ToNot(BeNil())
+	})
+
+	It("removes handled events from the queue files while running", func() {
+		q := openQueue(callback.WithWorkers(4))
+		defer q.Close()
+		var n int32
+		stop := run(q, callback.EventHandlerFunc(func(ctx context.Context, e *callback.Event) error {
+			atomic.AddInt32(&n, 1)
+			return nil
+		}))
+		defer stop()
+		e, err := callback.ParseEvent([]byte(signedPayload(time.Now(), callback.SignatureRequestSigned)))
+		Expect(err).To(BeNil())
+		for i := 0; i < 1000; i++ {
+			Expect(q.HandleEvent(context.Background(), e)).To(Succeed())
+		}
+		Eventually(func() int32 { return atomic.LoadInt32(&n) }).Should(Equal(int32(1000)))
+
+		lines := func(name string) int {
+			b, err := ioutil.ReadFile(filepath.Join(dir, name))
+			Expect(err).To(BeNil())
+			return strings.Count(string(b), "\n")
+		}
+		Expect(lines("journal.jsonl")).To(BeNumerically("<", 300))
+		Expect(lines("acks")).To(BeNumerically("<", 300))
+	})
+
+	It("keeps unhandled events across restarts", func() {
+		q := openQueue()
+		for _, eventType := range []string{callback.SignatureRequestSigned, callback.SignatureRequestAllSigned} {
+			e, err := callback.ParseEvent([]byte(signedPayload(time.Now(), eventType)))
+			Expect(err).To(BeNil())
+			Expect(q.HandleEvent(context.Background(), e)).To(Succeed())
+		}
+		first := make(chan struct{})
+		stop := run(q, callback.EventHandlerFunc(func(ctx context.Context, e *callback.Event) error {
+			if e.Type() == callback.SignatureRequestSigned {
+				close(first)
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+		Eventually(first).Should(BeClosed())
+		stop()
+		Expect(q.Close()).To(Succeed())
+
+		q = openQueue()
+		defer q.Close()
+		stop = run(q, record)
+		defer stop()
+		var e *callback.Event
+		Eventually(handled).Should(Receive(&e))
+		Expect(e.Type()).To(Equal(callback.SignatureRequestAllSigned))
+		Consistently(handled, 50*time.Millisecond).ShouldNot(Receive())
+	})
+})
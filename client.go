@@ -0,0 +1,97 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client gives access to all HelloSign api groups. The groups share one transport,
+// rate limit state and configuration.
+type Client struct {
+	Account          *AccountAPI
+	APIApp           *APIAppAPI
+	BulkSendJob      *BulkSendJobAPI
+	Embedded         *EmbeddedAPI
+	SignatureRequest *SignatureRequestAPI
+	Team             *TeamAPI
+	Template         *TemplateAPI
+	UnclaimedDraft   *UnclaimedDraftAPI
+}
+
+// NewClient creates a new api client for all HelloSign endpoints.
+func NewClient(apiKey string, opts ...Option) *Client {
+	return newClient(newHellosign(apiKey, opts...))
+}
+
+func newClient(hs *hellosign) *Client {
+	return &Client{
+		Account:          &AccountAPI{hs},
+		APIApp:           &APIAppAPI{hs},
+		BulkSendJob:      &BulkSendJobAPI{hs},
+		Embedded:         &EmbeddedAPI{hs},
+		SignatureRequest: &SignatureRequestAPI{hs},
+		Team:             &TeamAPI{hs},
+		Template:         &TemplateAPI{hs},
+		UnclaimedDraft:   &UnclaimedDraftAPI{hs},
+	}
+}
+
+// RateLimitSnapshot returns the rate limit state reported by the last response. It is safe for concurrent use.
+func (c *Client) RateLimitSnapshot() RateLimit {
+	return c.Account.RateLimitSnapshot()
+}
+
+// Option configures an api client.
+type Option func(*hellosign)
+
+// WithHTTPClient sets the http client used to perform requests. Defaults to http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *hellosign) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithBaseURL sets the url that all endpoints, including file downloads, are resolved against. Use it to
+// point the client at a proxy, a regional endpoint or a local stand-in such as an httptest server.
+// Defaults to https://api.hellosign.com/v3.
+func WithBaseURL(baseURL string) Option {
+	return func(c *hellosign) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *hellosign) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets a time limit for requests, including reading the response body. The http
+// client given to WithHTTPClient is not modified, a copy with the timeout set is used instead.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *hellosign) {
+		c.timeout = timeout
+	}
+}
+
+// WithStrictWarnings makes api calls fail with an APIWarn error when the response contains warnings,
+// for example about parameters the api ignored. Useful to catch mistakes in tests.
+//
+// The api has already handled such a call, strict mode does not undo it: a signature request sent
+// with warnings exists anyway. The result of the call is returned along with the APIWarn error so
+// that, for example, the id of the created signature request is not lost.
+func WithStrictWarnings() Option {
+	return func(c *hellosign) {
+		c.strictWarnings = true
+	}
+}
@@ -0,0 +1,160 @@
+package hellosign_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		apiKey string
+	)
+
+	_ = BeforeEach(func() {
+		apiKey = "asdf"
+	})
+
+	It("exposes all api groups", func() {
+		client := hellosign.NewClient(apiKey)
+		Expect(client.Account).ToNot(BeNil())
+		Expect(client.APIApp).ToNot(BeNil())
+		Expect(client.BulkSendJob).ToNot(BeNil())
+		Expect(client.Embedded).ToNot(BeNil())
+		Expect(client.SignatureRequest).ToNot(BeNil())
+		Expect(client.Team).ToNot(BeNil())
+		Expect(client.Template).ToNot(BeNil())
+		Expect(client.UnclaimedDraft).ToNot(BeNil())
+	})
+
+	It("applies options to every request", func() {
+		baseURL := "https://hellosign.example.com/v3"
+		httpmock.RegisterResponder(http.MethodGet, baseURL+"/account",
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("User-Agent")).To(Equal("test-agent/1.0"))
+				return httpmock.NewStringResponse(http.StatusOK, `{"account": {"account_id": "1"}}`), nil
+			})
+		httpmock.RegisterResponder(http.MethodGet, baseURL+"/team",
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("User-Agent")).To(Equal("test-agent/1.0"))
+				return httpmock.NewStringResponse(http.StatusOK, `{"team": {"name": "team"}}`), nil
+			})
+		client := hellosign.NewClient(apiKey,
+			hellosign.WithHTTPClient(&http.Client{}),
+			hellosign.WithBaseURL(baseURL),
+			hellosign.WithUserAgent("test-agent/1.0"),
+			hellosign.WithTimeout(time.Second))
+		acc, err := client.Account.Get()
+		Expect(err).To(BeNil())
+		Expect(acc.AccountID).To(Equal("1"))
+		team, err := client.Team.Get()
+		Expect(err).To(BeNil())
+		Expect(team.Name).To(Equal("team"))
+	})
+
+	Describe("with warnings", func() {
+		_ = BeforeEach(func() {
+			httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("team"),
+				httpmock.NewStringResponder(http.StatusOK, `{
+					"team": {"name": "team"},
+					"warnings": [{"warning_msg": "Parameter foo is unknown", "warning_name": "unknown_parameter"}]
+				}`))
+		})
+
+		It("records warnings of successful responses", func() {
+			client := hellosign.NewClient(apiKey)
+			meta := &hellosign.ResponseMeta{}
+			team, err := client.Team.GetContext(hellosign.ContextWithResponseMeta(context.Background(), meta))
+			Expect(err).To(BeNil())
+			Expect(team.Name).To(Equal("team"))
+			Expect(meta.StatusCode).To(Equal(http.StatusOK))
+			Expect(meta.Warnings).To(Equal([]hellosign.Warning{
+				{Message: "Parameter foo is unknown", Name: "unknown_parameter"},
+			}))
+		})
+
+		It("fails on warnings in strict mode", func() {
+			client := hellosign.NewClient(apiKey, hellosign.WithStrictWarnings())
+			team, err := client.Team.Get()
+			var warnErr hellosign.APIWarn
+			Expect(errors.As(err, &warnErr)).To(BeTrue())
+			Expect(warnErr.Warnings).To(HaveLen(1))
+			Expect(warnErr.Warnings[0].Name).To(Equal("unknown_parameter"))
+			Expect(team.Name).To(Equal("team"))
+		})
+
+		It("returns what was created along with the warnings in strict mode", func() {
+			httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL("signature_request/send"),
+				httpmock.NewStringResponder(http.StatusOK, `{
+					"signature_request": {"signature_request_id": "created"},
+					"warnings": [{"warning_msg": "Parameter foo is unknown", "warning_name": "unknown_parameter"}]
+				}`))
+			client := hellosign.NewClient(apiKey, hellosign.WithStrictWarnings())
+			sigReq, err := client.SignatureRequest.Send(hellosign.SigReqSendParms{
+				FileURL: []string{"https://example.com/a.pdf"},
+				Signers: []hellosign.SigReqSigner{{Name: "Jack", EmailAddress: "jack@example.com"}},
+			})
+			var warnErr hellosign.APIWarn
+			Expect(errors.As(err, &warnErr)).To(BeTrue())
+			Expect(sigReq).ToNot(BeNil())
+			Expect(sigReq.SignatureRequestID).To(Equal("created"))
+		})
+
+		It("returns no result when a failed response only has warnings", func() {
+			httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("team"),
+				httpmock.NewStringResponder(http.StatusBadRequest, `{
+					"warnings": [{"warning_msg": "Team not found", "warning_name": "not_found"}]
+				}`))
+			team, err := hellosign.NewClient(apiKey, hellosign.WithStrictWarnings()).Team.Get()
+			var warnErr hellosign.APIWarn
+			Expect(errors.As(err, &warnErr)).To(BeTrue())
+			Expect(team).To(BeNil())
+		})
+	})
+
+	Describe("with a base url", func() {
+		var (
+			server *httptest.Server
+			client *hellosign.Client
+		)
+
+		_ = BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/v3/account", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"account": {"account_id": "local"}}`)
+			})
+			mux.HandleFunc("/v3/signature_request/files/abc", func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Query().Get("file_type")).To(Equal("pdf"))
+				fmt.Fprint(w, "%PDF")
+			})
+			server = httptest.NewServer(mux)
+			client = hellosign.NewClient(apiKey,
+				hellosign.WithHTTPClient(server.Client()),
+				hellosign.WithBaseURL(server.URL+"/v3/"))
+		})
+
+		_ = AfterEach(func() {
+			server.Close()
+		})
+
+		It("resolves endpoints against the base url", func() {
+			acc, err := client.Account.Get()
+			Expect(err).To(BeNil())
+			Expect(acc.AccountID).To(Equal("local"))
+		})
+
+		It("downloads files from the base url", func() {
+			b, _, err := client.SignatureRequest.Files("abc", "pdf", false)
+			Expect(err).To(BeNil())
+			Expect(string(b)).To(Equal("%PDF"))
+		})
+	})
+})
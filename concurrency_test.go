@@ -0,0 +1,91 @@
+package hellosign_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Concurrency", func() {
+	const goroutines = 50
+
+	var (
+		requests int64
+	)
+
+	_ = BeforeEach(func() {
+		requests = 0
+		httpmock.RegisterResponder(http.MethodGet, `=~^`+hellosign.GetEptURL("signature_request")+`/(\w+)\z`,
+			func(req *http.Request) (*http.Response, error) {
+				n := atomic.AddInt64(&requests, 1)
+				id := httpmock.MustGetSubmatch(req, 1)
+				if id == "missing" {
+					return httpmock.NewStringResponse(http.StatusNotFound, `{"error": {"error_msg": "Not found", "error_name": "not_found"}}`), nil
+				}
+				resp := httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"signature_request": {"signature_request_id": "%s"}}`, id))
+				resp.Header.Set("X-Ratelimit-Limit", "2000")
+				resp.Header.Set("X-Ratelimit-Limit-Remaining", strconv.FormatInt(2000-n, 10))
+				return resp, nil
+			})
+	})
+
+	It("records per call response metadata when shared between goroutines", func() {
+		client := hellosign.NewClient("asdf", hellosign.WithRateLimitWait())
+		var wg sync.WaitGroup
+		// Every goroutine reports at most two errors, one per check, so none of them blocks.
+		errs := make(chan error, 2*goroutines)
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				id, expected := fmt.Sprintf("req%d", i), http.StatusOK
+				if i%5 == 0 {
+					id, expected = "missing", http.StatusNotFound
+				}
+				meta := &hellosign.ResponseMeta{}
+				ctx := hellosign.ContextWithResponseMeta(context.Background(), meta)
+				sigReq, err := client.SignatureRequest.GetContext(ctx, id)
+				if expected == http.StatusOK && (err != nil || sigReq.SignatureRequestID != id) {
+					errs <- fmt.Errorf("unexpected result for %s: %v", id, err)
+				}
+				if meta.StatusCode != expected {
+					errs <- fmt.Errorf("status code for %s was %d, expected %d", id, meta.StatusCode, expected)
+				}
+				_ = client.RateLimitSnapshot()
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			Expect(err).To(BeNil())
+		}
+		Expect(atomic.LoadInt64(&requests)).To(Equal(int64(goroutines)))
+		Expect(client.RateLimitSnapshot().Limit).To(Equal(uint64(2000)))
+	})
+
+	It("shares state between api groups created by one client", func() {
+		client := hellosign.NewClient("asdf")
+		var wg sync.WaitGroup
+		for i := 0; i < goroutines; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				_, _ = client.SignatureRequest.Get(fmt.Sprintf("req%d", i))
+			}(i)
+			go func() {
+				defer wg.Done()
+				_ = client.Template.RateLimitSnapshot()
+			}()
+		}
+		wg.Wait()
+		Expect(client.Template.RateLimitSnapshot().Limit).To(Equal(uint64(2000)))
+	})
+})
@@ -6,6 +6,36 @@
 
 Package hellosign implements various API clients for the HelloSign platform.
 
+Usage
+
+A Client gives access to all api groups, configured once and sharing one transport:
+
+	client := hellosign.NewClient(apiKey, hellosign.WithTimeout(30*time.Second))
+	sigReq, err := client.SignatureRequest.Get(signatureRequestID)
+
+OAuth
+
+Api apps act on behalf of other accounts with OAuth. Send users to the url returned by
+OAuthAPI.AuthorizationURL, exchange the code HelloSign calls back with for a token and
+create a client with it:
+
+	tok, err := hellosign.NewOAuthAPI(clientID, clientSecret).ExchangeCode(code, state)
+	client := hellosign.NewOAuthClient(tok.AccessToken)
+
+Tokens expire. A RefreshingTokenSource refreshes them when needed and saves them to a TokenStore,
+such as an encrypted FileTokenStore, so they survive restarts:
+
+	ts := hellosign.NewRefreshingTokenSource(oauth, store)
+	client := hellosign.NewClient("", hellosign.WithTokenSource(ts))
+
+Services working for many accounts can keep a client per account in a ClientPool, which creates
+clients on first use, evicts idle ones and keeps the rate limit state of every account apart.
+
+Concurrency
+
+Clients are safe for concurrent use. Information about the response to a single call, such as the status
+code, is recorded in a ResponseMeta attached to the call context with ContextWithResponseMeta.
+
 Charges
 
 The creation of live signature requests is not free and requires a paid API plan (https://www.hellosign.com/api/pricing). The
@@ -16,6 +46,7 @@ Rate Limits
 
 By default, you can make up to 2000 requests per hour for standard API requests, and 500 requests per hour for higher tier API requests.
 In test mode, you can do 50 requests per hour. Exceptions can be made for customers with higher volumes.
+Clients created with WithRateLimitWait wait for the limit to reset instead of sending requests that would be rejected.
 
 */
 package hellosign
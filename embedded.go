@@ -4,7 +4,10 @@
 
 package hellosign
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // EmbeddedAPI used for embedded signature manipulations.
 type EmbeddedAPI struct {
@@ -12,8 +15,8 @@ type EmbeddedAPI struct {
 }
 
 // NewEmbeddedAPI creates a new api client for embedded operations.
-func NewEmbeddedAPI(apiKey string) *EmbeddedAPI {
-	return &EmbeddedAPI{newHellosign(apiKey)}
+func NewEmbeddedAPI(apiKey string, opts ...Option) *EmbeddedAPI {
+	return &EmbeddedAPI{newHellosign(apiKey, opts...)}
 }
 
 // EmbeddedURL is an URL with an expiration time.
@@ -28,19 +31,31 @@ type embeddedURLRaw struct {
 
 // GetSignURL retrieves an embedded object containing a signature url that can be opened in an iFrame.
 func (c *EmbeddedAPI) GetSignURL(signatureID string) (*EmbeddedURL, error) {
+	return c.GetSignURLContext(context.Background(), signatureID)
+}
+
+// GetSignURLContext is like GetSignURL but uses ctx for the request.
+func (c *EmbeddedAPI) GetSignURLContext(ctx context.Context, signatureID string) (*EmbeddedURL, error) {
 	url := &embeddedURLRaw{}
-	if err := c.getAndParse(fmt.Sprintf("embedded/sign_url/%s", signatureID), nil, url); err != nil {
+	err := c.getAndParse(ctx, fmt.Sprintf("embedded/sign_url/%s", signatureID), nil, url)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &url.Embedded, nil
+	return &url.Embedded, err
 }
 
 // GetTemplateEditURL retrieves an embedded object containing a template url that can be opened in an iFrame.
 // Note that only templates created via the embedded template process are available to be edited with this endpoint.
 func (c *EmbeddedAPI) GetTemplateEditURL(templateID string) (*EmbeddedURL, error) {
+	return c.GetTemplateEditURLContext(context.Background(), templateID)
+}
+
+// GetTemplateEditURLContext is like GetTemplateEditURL but uses ctx for the request.
+func (c *EmbeddedAPI) GetTemplateEditURLContext(ctx context.Context, templateID string) (*EmbeddedURL, error) {
 	url := &embeddedURLRaw{}
-	if err := c.getAndParse(fmt.Sprintf("embedded/edit_url/%s", templateID), nil, url); err != nil {
+	err := c.getAndParse(ctx, fmt.Sprintf("embedded/edit_url/%s", templateID), nil, url)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &url.Embedded, nil
+	return &url.Embedded, err
 }
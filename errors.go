@@ -0,0 +1,162 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Errors that api errors can be checked against with errors.Is.
+var (
+	ErrBadRequest       = errors.New("hellosign: bad request")
+	ErrUnauthorized     = errors.New("hellosign: unauthorized")
+	ErrPaymentRequired  = errors.New("hellosign: payment required")
+	ErrForbidden        = errors.New("hellosign: forbidden")
+	ErrNotFound         = errors.New("hellosign: not found")
+	ErrRateLimited      = errors.New("hellosign: rate limited")
+	ErrUnexpectedStatus = errors.New("hellosign: unexpected status code")
+)
+
+// statusErr returns the error a response code is checked against.
+func statusErr(code int) error {
+	switch code {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusPaymentRequired:
+		return ErrPaymentRequired
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// unparsedStatusErr returns the error for responses without an api error.
+func unparsedStatusErr(code int) error {
+	if err := statusErr(code); err != nil {
+		return err
+	}
+	return ErrUnexpectedStatus
+}
+
+// nameErr returns the error an api error name is checked against.
+func nameErr(name string) error {
+	switch name {
+	case "bad_request":
+		return ErrBadRequest
+	case "unauthorized":
+		return ErrUnauthorized
+	case "payment_required":
+		return ErrPaymentRequired
+	case "forbidden":
+		return ErrForbidden
+	case "not_found":
+		return ErrNotFound
+	case "exceeded_rate":
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// Is reports whether the api error matches one of the package errors such as ErrNotFound.
+func (a APIErr) Is(target error) bool {
+	return target != nil && (statusErr(a.Code) == target || nameErr(a.Name) == target)
+}
+
+// BadRequestErr is returned when the api rejects a request because of an invalid parameter.
+type BadRequestErr struct {
+	APIErr
+	Field string // Name of the offending parameter, empty when unknown
+}
+
+// Unwrap returns the underlying api error.
+func (e BadRequestErr) Unwrap() error {
+	return e.APIErr
+}
+
+// RateLimitErr is returned when the api rejects a request because the rate limit has been exceeded.
+type RateLimitErr struct {
+	APIErr
+	Reset time.Time // When requests are accepted again, zero when unknown
+}
+
+// Unwrap returns the underlying api error.
+func (e RateLimitErr) Unwrap() error {
+	return e.APIErr
+}
+
+// ResponseErr wraps the error caused by an api response with details useful for logging.
+type ResponseErr struct {
+	Method     string // Method of the request
+	Endpoint   string // Url of the request, without query parameters
+	StatusCode int    // HTTP response code
+	Body       []byte // Raw response body
+	Err        error  // The api error, or a package error such as ErrUnexpectedStatus
+}
+
+func (e *ResponseErr) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Method, e.Endpoint, e.Err)
+}
+
+// Unwrap returns the wrapped error.
+func (e *ResponseErr) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the response code matches one of the package errors such as ErrNotFound.
+func (e *ResponseErr) Is(target error) bool {
+	return target != nil && statusErr(e.StatusCode) == target
+}
+
+func newResponseErr(resp *http.Response, body []byte, err error) *ResponseErr {
+	respErr := &ResponseErr{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		Err:        err,
+	}
+	if req := resp.Request; req != nil {
+		respErr.Method = req.Method
+		u := *req.URL
+		u.RawQuery = ""
+		u.User = nil
+		respErr.Endpoint = u.String()
+	}
+	return respErr
+}
+
+var errFieldRe = regexp.MustCompile(`(?i)parameter:?\s+([\w\[\]\.]+)`)
+
+// typedAPIErr returns the most specific error type for an api error.
+func typedAPIErr(resp *http.Response, apiErr APIErr, path string) error {
+	switch {
+	case apiErr.Is(ErrRateLimited):
+		rlErr := RateLimitErr{APIErr: apiErr}
+		if d, ok := parseRetryAfter(resp.Header.Get(retryAfter)); ok {
+			rlErr.Reset = time.Now().Add(d)
+		} else if reset, err := strconv.ParseInt(resp.Header.Get(xRateLimitReset), 10, 64); err == nil {
+			rlErr.Reset = time.Unix(reset, 0)
+		}
+		return rlErr
+	case apiErr.Is(ErrBadRequest):
+		brErr := BadRequestErr{APIErr: apiErr, Field: path}
+		if brErr.Field == "" {
+			if m := errFieldRe.FindStringSubmatch(apiErr.Message); m != nil {
+				brErr.Field = m[1]
+			}
+		}
+		return brErr
+	}
+	return apiErr
+}
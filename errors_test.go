@@ -0,0 +1,85 @@
+package hellosign_test
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Errors", func() {
+	var (
+		client *hellosign.SignatureRequestAPI
+	)
+
+	_ = BeforeEach(func() {
+		client = hellosign.NewSignatureRequestAPI("asdf")
+	})
+
+	respond := func(code int, body string) {
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("signature_request/abc"),
+			httpmock.NewStringResponder(code, body))
+	}
+
+	It("matches api errors against package errors", func() {
+		for code, target := range map[int]error{
+			http.StatusUnauthorized:    hellosign.ErrUnauthorized,
+			http.StatusPaymentRequired: hellosign.ErrPaymentRequired,
+			http.StatusForbidden:       hellosign.ErrForbidden,
+			http.StatusNotFound:        hellosign.ErrNotFound,
+		} {
+			respond(code, `{"error": {"error_msg": "failed", "error_name": "some_error"}}`)
+			_, err := client.Get("abc")
+			Expect(errors.Is(err, target)).To(BeTrue())
+			Expect(errors.Is(err, hellosign.ErrBadRequest)).To(BeFalse())
+			var apiErr hellosign.APIErr
+			Expect(errors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.Code).To(Equal(code))
+		}
+	})
+
+	It("keeps the request and raw response", func() {
+		body := `{"error": {"error_msg": "Not found", "error_name": "not_found"}}`
+		respond(http.StatusNotFound, body)
+		_, err := client.Get("abc")
+		var respErr *hellosign.ResponseErr
+		Expect(errors.As(err, &respErr)).To(BeTrue())
+		Expect(respErr.Method).To(Equal(http.MethodGet))
+		Expect(respErr.Endpoint).To(Equal(hellosign.GetEptURL("signature_request/abc")))
+		Expect(respErr.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(string(respErr.Body)).To(Equal(body))
+	})
+
+	It("matches responses without an api error", func() {
+		respond(http.StatusNotFound, `<html>Not found</html>`)
+		_, err := client.Get("abc")
+		Expect(errors.Is(err, hellosign.ErrNotFound)).To(BeTrue())
+	})
+
+	It("reports the offending field of bad requests", func() {
+		respond(http.StatusBadRequest, `{"error": {"error_msg": "Invalid parameter: signers[0][email_address]", "error_name": "bad_request"}}`)
+		_, err := client.Get("abc")
+		Expect(errors.Is(err, hellosign.ErrBadRequest)).To(BeTrue())
+		var brErr hellosign.BadRequestErr
+		Expect(errors.As(err, &brErr)).To(BeTrue())
+		Expect(brErr.Field).To(Equal("signers[0][email_address]"))
+	})
+
+	It("reports when rate limited requests are accepted again", func() {
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("signature_request/abc"),
+			func(req *http.Request) (*http.Response, error) {
+				resp := httpmock.NewStringResponse(http.StatusTooManyRequests, `{"error": {"error_msg": "Rate limit exceeded", "error_name": "exceeded_rate"}}`)
+				resp.Header.Set("Retry-After", "60")
+				return resp, nil
+			})
+		_, err := client.Get("abc")
+		Expect(errors.Is(err, hellosign.ErrRateLimited)).To(BeTrue())
+		var rlErr hellosign.RateLimitErr
+		Expect(errors.As(err, &rlErr)).To(BeTrue())
+		Expect(rlErr.Reset).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+	})
+})
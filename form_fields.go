@@ -0,0 +1,230 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"fmt"
+)
+
+// Types of the form fields placed on documents.
+const (
+	FieldSignature  = "signature"
+	FieldInitials   = "initials"
+	FieldText       = "text"
+	FieldCheckbox   = "checkbox"
+	FieldDateSigned = "date_signed"
+	FieldDropdown   = "dropdown"
+	FieldRadio      = "radio"
+	FieldTextMerge  = "text-merge"
+)
+
+// Validation types of text fields.
+const (
+	ValidationNumbersOnly                  = "numbers_only"
+	ValidationLettersOnly                  = "letters_only"
+	ValidationPhoneNumber                  = "phone_number"
+	ValidationBankRoutingNumber            = "bank_routing_number"
+	ValidationBankAccountNumber            = "bank_account_number"
+	ValidationEmailAddress                 = "email_address"
+	ValidationZipCode                      = "zip_code"
+	ValidationSocialSecurityNumber         = "social_security_number"
+	ValidationEmployerIdentificationNumber = "employer_identification_number"
+	ValidationCustomRegex                  = "custom_regex"
+)
+
+// Requirements of form field groups.
+const (
+	GroupRequire0To1    = "require_0-1"
+	GroupRequire1       = "require_1"
+	GroupRequire1OrMore = "require_1-ormore"
+	GroupRequire0OrMore = "require_0-ormore"
+)
+
+// Font sizes accepted for text fields, in points.
+const (
+	minFontSize = 7
+	maxFontSize = 49
+)
+
+var fieldTypes = map[string]bool{
+	FieldSignature:  true,
+	FieldInitials:   true,
+	FieldText:       true,
+	FieldCheckbox:   true,
+	FieldDateSigned: true,
+	FieldDropdown:   true,
+	FieldRadio:      true,
+	FieldTextMerge:  true,
+}
+
+var validationTypes = map[string]bool{
+	ValidationNumbersOnly:                  true,
+	ValidationLettersOnly:                  true,
+	ValidationPhoneNumber:                  true,
+	ValidationBankRoutingNumber:            true,
+	ValidationBankAccountNumber:            true,
+	ValidationEmailAddress:                 true,
+	ValidationZipCode:                      true,
+	ValidationSocialSecurityNumber:         true,
+	ValidationEmployerIdentificationNumber: true,
+	ValidationCustomRegex:                  true,
+}
+
+var groupRequirements = map[string]bool{
+	GroupRequire0To1:    true,
+	GroupRequire1:       true,
+	GroupRequire1OrMore: true,
+	GroupRequire0OrMore: true,
+}
+
+// DocumentFormField is a form field placed on a document. Coordinates and sizes are in pixels
+// from the top left corner of the page, pages are numbered from 1.
+type DocumentFormField struct {
+	APIID                 string   `json:"api_id"` // Unique across all documents
+	Name                  string   `json:"name"`
+	Type                  string   `json:"type"`
+	X                     uint64   `json:"x"`
+	Y                     uint64   `json:"y"`
+	Width                 uint64   `json:"width"`
+	Height                uint64   `json:"height"`
+	Page                  uint64   `json:"page"`
+	Signer                int      `json:"signer"` // Index of the signer in Signers
+	Required              bool     `json:"required"`
+	ValidationType        string   `json:"validation_type,omitempty"` // Text fields only
+	ValidationCustomRegex string   `json:"validation_custom_regex,omitempty"`
+	FontSize              uint64   `json:"font_size,omitempty"` // Text fields only
+	Options               []string `json:"options,omitempty"`   // Dropdown fields only
+	Content               string   `json:"content,omitempty"`   // Default option of dropdown fields
+	Group                 string   `json:"group,omitempty"`     // Group id of radio and checkbox fields
+	IsChecked             bool     `json:"is_checked,omitempty"`
+}
+
+// FormFieldGroup sets how many of the checkbox or radio fields of a group must be filled in.
+type FormFieldGroup struct {
+	GroupID     string `json:"group_id"`
+	GroupLabel  string `json:"group_label"`
+	Requirement string `json:"requirement"`
+}
+
+// FormFieldsPerDocument holds the form fields of each document, in the order the documents are given.
+type FormFieldsPerDocument [][]DocumentFormField
+
+// Add appends fields to the document at index document and returns the updated form fields.
+func (f FormFieldsPerDocument) Add(document int, fields ...DocumentFormField) FormFieldsPerDocument {
+	for len(f) <= document {
+		f = append(f, []DocumentFormField{})
+	}
+	f[document] = append(f[document], fields...)
+	return f
+}
+
+// validateFormFields checks the form fields against the number of signers and documents of a
+// request and the groups they refer to. Signer indices are not checked when there are no signers.
+func validateFormFields(fields FormFieldsPerDocument, groups []FormFieldGroup, numSigners, numDocs int) error {
+	if len(fields) > numDocs {
+		return fmt.Errorf("Form fields given for %d documents, but only %d documents given", len(fields), numDocs)
+	}
+	groupIDs := map[string]int{}
+	for _, g := range groups {
+		if g.GroupID == "" {
+			return fmt.Errorf("Specify group id of form field group %q", g.GroupLabel)
+		}
+		if _, ok := groupIDs[g.GroupID]; ok {
+			return fmt.Errorf("Form field group %s given more than once", g.GroupID)
+		}
+		if !groupRequirements[g.Requirement] {
+			return fmt.Errorf("Form field group %s: invalid requirement %q", g.GroupID, g.Requirement)
+		}
+		groupIDs[g.GroupID] = 0
+	}
+	apiIDs := map[string]bool{}
+	for doc, docFields := range fields {
+		for _, ff := range docFields {
+			if ff.APIID == "" {
+				return fmt.Errorf("Document %d: specify api id of every form field", doc)
+			}
+			if apiIDs[ff.APIID] {
+				return fmt.Errorf("Form field %s given more than once", ff.APIID)
+			}
+			apiIDs[ff.APIID] = true
+			if err := validateFormField(ff, numSigners); err != nil {
+				return fmt.Errorf("Document %d, form field %s: %v", doc, ff.APIID, err)
+			}
+			if ff.Group != "" {
+				n, ok := groupIDs[ff.Group]
+				if !ok {
+					return fmt.Errorf("Document %d, form field %s: unknown group %s", doc, ff.APIID, ff.Group)
+				}
+				groupIDs[ff.Group] = n + 1
+			}
+		}
+	}
+	for _, g := range groups {
+		if groupIDs[g.GroupID] < 2 {
+			return fmt.Errorf("Form field group %s needs at least two fields", g.GroupID)
+		}
+	}
+	return nil
+}
+
+func validateFormField(ff DocumentFormField, numSigners int) error {
+	if !fieldTypes[ff.Type] {
+		return fmt.Errorf("invalid type %q", ff.Type)
+	}
+	if ff.Page == 0 {
+		return fmt.Errorf("pages are numbered from 1")
+	}
+	if ff.Width == 0 || ff.Height == 0 {
+		return fmt.Errorf("specify width and height")
+	}
+	if ff.Type != FieldTextMerge && numSigners > 0 && (ff.Signer < 0 || ff.Signer >= numSigners) {
+		return fmt.Errorf("signer index %d out of range, %d signers given", ff.Signer, numSigners)
+	}
+	if ff.ValidationType != "" {
+		if ff.Type != FieldText {
+			return fmt.Errorf("validation type is only allowed on text fields")
+		}
+		if !validationTypes[ff.ValidationType] {
+			return fmt.Errorf("invalid validation type %q", ff.ValidationType)
+		}
+		if (ff.ValidationType == ValidationCustomRegex) != (ff.ValidationCustomRegex != "") {
+			return fmt.Errorf("specify a custom regex together with the custom_regex validation type")
+		}
+	}
+	if ff.FontSize != 0 {
+		if ff.Type != FieldText && ff.Type != FieldTextMerge {
+			return fmt.Errorf("font size is only allowed on text fields")
+		}
+		if ff.FontSize < minFontSize || ff.FontSize > maxFontSize {
+			return fmt.Errorf("font size must be between %d and %d", minFontSize, maxFontSize)
+		}
+	}
+	if ff.Type == FieldDropdown {
+		if len(ff.Options) == 0 {
+			return fmt.Errorf("specify options of dropdown fields")
+		}
+		if ff.Content != "" && !containsString(ff.Options, ff.Content) {
+			return fmt.Errorf("content %q is not one of the options", ff.Content)
+		}
+	} else if len(ff.Options) > 0 {
+		return fmt.Errorf("options are only allowed on dropdown fields")
+	}
+	if ff.Type == FieldRadio && ff.Group == "" {
+		return fmt.Errorf("specify group of radio fields")
+	}
+	if ff.Group != "" && ff.Type != FieldRadio && ff.Type != FieldCheckbox {
+		return fmt.Errorf("groups are only allowed on radio and checkbox fields")
+	}
+	return nil
+}
+
+func containsString(arr []string, s string) bool {
+	for _, v := range arr {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
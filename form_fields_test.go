@@ -0,0 +1,144 @@
+package hellosign_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Form fields", func() {
+	var (
+		api    *hellosign.SignatureRequestAPI
+		params map[string]string
+		posted bool
+	)
+
+	_ = BeforeEach(func() {
+		api = hellosign.NewSignatureRequestAPI("asdf")
+		params = nil
+		posted = false
+		httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL("signature_request/send"),
+			func(req *http.Request) (*http.Response, error) {
+				var err error
+				posted = true
+				params, err = parseRequestParameters(req)
+				Expect(err).To(BeNil())
+				return httpmock.NewStringResponse(http.StatusOK, `{"signature_request": {}}`), nil
+			})
+	})
+
+	send := func(fields hellosign.FormFieldsPerDocument, groups []hellosign.FormFieldGroup) error {
+		_, err := api.Send(hellosign.SigReqSendParms{
+			FileURL: []string{"https://example.com/a.pdf", "https://example.com/b.pdf"},
+			Signers: []hellosign.SigReqSigner{
+				{Name: "Jack", EmailAddress: "jack@example.com"},
+				{Name: "Jill", EmailAddress: "jill@example.com"},
+			},
+			FormFieldsPerDocument: fields,
+			FormFieldGroups:       groups,
+		})
+		return err
+	}
+
+	field := func(apiID, fieldType string) hellosign.DocumentFormField {
+		return hellosign.DocumentFormField{APIID: apiID, Type: fieldType, X: 10, Y: 20, Width: 100, Height: 16, Page: 1}
+	}
+
+	It("sends form fields and groups as json", func() {
+		text := field("phone", hellosign.FieldText)
+		text.Signer = 1
+		text.ValidationType = hellosign.ValidationPhoneNumber
+		text.FontSize = 12
+		dropdown := field("color", hellosign.FieldDropdown)
+		dropdown.Options = []string{"red", "blue"}
+		dropdown.Content = "blue"
+		yes, no := field("yes", hellosign.FieldRadio), field("no", hellosign.FieldRadio)
+		yes.Group, no.Group = "answer", "answer"
+		fields := hellosign.FormFieldsPerDocument{}.
+			Add(1, text, dropdown).
+			Add(0, field("sig", hellosign.FieldSignature)).
+			Add(1, yes, no)
+		Expect(fields).To(HaveLen(2))
+		Expect(fields[1]).To(HaveLen(4))
+
+		err := send(fields, []hellosign.FormFieldGroup{
+			{GroupID: "answer", GroupLabel: "Answer", Requirement: hellosign.GroupRequire1},
+		})
+		Expect(err).To(BeNil())
+
+		sent := [][]map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(params["form_fields_per_document"]), &sent)).To(Succeed())
+		Expect(sent).To(HaveLen(2))
+		Expect(sent[0][0]).To(Equal(map[string]interface{}{
+			"api_id": "sig", "name": "", "type": "signature", "x": 10.0, "y": 20.0, "width": 100.0,
+			"height": 16.0, "page": 1.0, "signer": 0.0, "required": false,
+		}))
+		Expect(sent[1][0]["validation_type"]).To(Equal("phone_number"))
+		Expect(sent[1][0]["font_size"]).To(Equal(12.0))
+		Expect(sent[1][0]["signer"]).To(Equal(1.0))
+		Expect(sent[1][1]["options"]).To(Equal([]interface{}{"red", "blue"}))
+		Expect(sent[1][3]["group"]).To(Equal("answer"))
+		Expect(params["form_field_groups"]).To(MatchJSON(`[{"group_id": "answer", "group_label": "Answer", "requirement": "require_1"}]`))
+	})
+
+	It("leaves form fields out when none are given", func() {
+		Expect(send(nil, nil)).To(Succeed())
+		Expect(params).ToNot(HaveKey("form_fields_per_document"))
+		Expect(params).ToNot(HaveKey("form_field_groups"))
+	})
+
+	It("validates form fields before sending", func() {
+		withSigner := field("sig", hellosign.FieldSignature)
+		withSigner.Signer = 2
+		noPage := field("sig", hellosign.FieldSignature)
+		noPage.Page = 0
+		noSize := field("sig", hellosign.FieldSignature)
+		noSize.Width = 0
+		badValidation := field("sig", hellosign.FieldSignature)
+		badValidation.ValidationType = hellosign.ValidationNumbersOnly
+		regex := field("txt", hellosign.FieldText)
+		regex.ValidationType = hellosign.ValidationCustomRegex
+		bigFont := field("txt", hellosign.FieldText)
+		bigFont.FontSize = 100
+		noOptions := field("dd", hellosign.FieldDropdown)
+		badContent := field("dd", hellosign.FieldDropdown)
+		badContent.Options, badContent.Content = []string{"a"}, "b"
+		radio := field("r", hellosign.FieldRadio)
+		unknownGroup := field("c", hellosign.FieldCheckbox)
+		unknownGroup.Group = "g"
+
+		for _, fields := range []hellosign.FormFieldsPerDocument{
+			hellosign.FormFieldsPerDocument{}.Add(2, field("sig", hellosign.FieldSignature)),
+			hellosign.FormFieldsPerDocument{}.Add(0, field("", hellosign.FieldSignature)),
+			hellosign.FormFieldsPerDocument{}.Add(0, field("sig", hellosign.FieldSignature)).Add(1, field("sig", hellosign.FieldText)),
+			hellosign.FormFieldsPerDocument{}.Add(0, field("sig", "stamp")),
+			hellosign.FormFieldsPerDocument{}.Add(0, withSigner),
+			hellosign.FormFieldsPerDocument{}.Add(0, noPage),
+			hellosign.FormFieldsPerDocument{}.Add(0, noSize),
+			hellosign.FormFieldsPerDocument{}.Add(0, badValidation),
+			hellosign.FormFieldsPerDocument{}.Add(0, regex),
+			hellosign.FormFieldsPerDocument{}.Add(0, bigFont),
+			hellosign.FormFieldsPerDocument{}.Add(0, noOptions),
+			hellosign.FormFieldsPerDocument{}.Add(0, badContent),
+			hellosign.FormFieldsPerDocument{}.Add(0, radio),
+			hellosign.FormFieldsPerDocument{}.Add(0, unknownGroup),
+		} {
+			Expect(send(fields, nil)).ToNot(Succeed())
+		}
+
+		one := field("c1", hellosign.FieldCheckbox)
+		one.Group = "g"
+		for _, groups := range [][]hellosign.FormFieldGroup{
+			{{GroupID: "g", Requirement: "require_2"}},
+			{{GroupID: "g", Requirement: hellosign.GroupRequire1}},
+			{{GroupID: "g", Requirement: hellosign.GroupRequire1}, {GroupID: "g", Requirement: hellosign.GroupRequire1}},
+		} {
+			Expect(send(hellosign.FormFieldsPerDocument{}.Add(0, one), groups)).ToNot(Succeed())
+		}
+		Expect(posted).To(BeFalse())
+	})
+})
@@ -5,13 +5,15 @@
 package hellosign
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
-	"strconv"
+	"time"
 
 	"io"
 
@@ -64,10 +66,13 @@ type APIErr struct {
 // APIWarn a list of warnings returned from the HelloSign API.
 type APIWarn struct {
 	Code     int // HTTP response code
-	Warnings []struct {
-		Message string
-		Name    string
-	}
+	Warnings []Warning
+}
+
+// Warning a warning returned from the HelloSign API, for example about an unknown parameter.
+type Warning struct {
+	Message string `json:"warning_msg"`
+	Name    string `json:"warning_name"`
 }
 
 func (a APIErr) Error() string {
@@ -82,19 +87,39 @@ func (a APIWarn) Error() string {
 	return outMsg
 }
 
+// hellosign is the shared state of the api clients. It is safe for concurrent use, configuration is
+// only set on creation and mutable state is guarded by locks.
 type hellosign struct {
-	apiKey             string
-	RateLimit          uint64 // Number of requests allowed per hour
-	RateLimitRemaining uint64 // Remaining number of requests this hour
-	RateLimitReset     uint64 // When the limit will be reset. In seconds from epoch
-	LastStatusCode     int
+	apiKey         string
+	tokenSource    TokenSource
+	baseURL        string
+	oauthURL       string
+	userAgent      string
+	timeout        time.Duration
+	httpClient     *http.Client
+	retryPolicy    RetryPolicy
+	rateLimiter    rateLimiter
+	rateLimitWait  bool
+	strictWarnings bool
 }
 
 // Initializes a new Hellosign API client.
-func newHellosign(apiKey string) *hellosign {
-	return &hellosign{
-		apiKey: apiKey,
-	}
+func newHellosign(apiKey string, opts ...Option) *hellosign {
+	c := &hellosign{
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		oauthURL:   oauthURL,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.timeout > 0 {
+		httpClient := *c.httpClient
+		httpClient.Timeout = c.timeout
+		c.httpClient = &httpClient
+	}
+	return c
 }
 
 func DumpRequest(req *http.Request) {
@@ -106,96 +131,162 @@ func DumpRequest(req *http.Request) {
 
 func (c *hellosign) perform(req *http.Request) (*http.Response, error) {
 	req.Header.Add("accept", "application/json")
-	req.SetBasicAuth(c.apiKey, "")
+	tok, err := c.authorize(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("user-agent", c.userAgent)
+	}
 	//DumpRequest(req)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
-	c.LastStatusCode = resp.StatusCode
+	if resp.StatusCode == http.StatusUnauthorized && tok != nil {
+		if resp, err = c.refreshAndRetry(req, resp, tok); err != nil {
+			return nil, err
+		}
+	}
+	resp.Request = req
+	c.recordResponseMeta(req, resp)
 	if resp.StatusCode >= 400 {
 		return nil, c.parseResponseError(resp)
 	}
-	for _, hk := range []string{xRatelimitLimit, xRatelimitLimitRemaining, xRateLimitReset} {
-		hv := resp.Header.Get(hk)
-		if hv == "" {
-			continue
-		}
-		hvui, pErr := strconv.ParseUint(hv, 10, 64)
-		if pErr != nil {
-			continue
+	return resp, err
+}
+
+// authorize sets the Authorization header from the token source, or the api key when there is
+// none. It returns the token used.
+func (c *hellosign) authorize(req *http.Request) (*OAuthToken, error) {
+	if c.tokenSource == nil {
+		if c.apiKey != "" {
+			req.SetBasicAuth(c.apiKey, "")
 		}
-		switch hk {
-		case xRatelimitLimit:
-			c.RateLimit = hvui
-		case xRatelimitLimitRemaining:
-			c.RateLimitRemaining = hvui
-		case xRateLimitReset:
-			c.RateLimitReset = hvui
+		return nil, nil
+	}
+	tok, err := c.tokenSource.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	return tok, nil
+}
+
+// refreshAndRetry sends the request again with a refreshed token when the token source can refresh
+// the rejected token and the body can be rewound. Otherwise the unauthorized response is returned.
+func (c *hellosign) refreshAndRetry(req *http.Request, resp *http.Response, rejected *OAuthToken) (*http.Response, error) {
+	refresher, ok := c.tokenSource.(TokenRefresher)
+	if !ok || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return resp, nil
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	tok, err := refresher.Refresh(req.Context(), rejected)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	if req.GetBody != nil {
+		if req.Body, err = req.GetBody(); err != nil {
+			return nil, err
 		}
 	}
-	return resp, err
+	return c.do(req)
 }
 
 func (c *hellosign) parseResponseError(resp *http.Response) error {
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return newResponseErr(resp, b, c.parseAPIErr(resp, b))
+}
+
+func (c *hellosign) parseAPIErr(resp *http.Response, b []byte) error {
 	e := &struct {
 		Err struct {
 			Msg  *string `json:"error_msg"`
 			Name *string `json:"error_name"`
+			Path string  `json:"error_path"`
 		} `json:"error"`
 	}{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return unparsedStatusErr(resp.StatusCode)
+	}
+	if e.Err.Name != nil {
+		apiErr := APIErr{Code: resp.StatusCode, Name: *e.Err.Name}
+		if e.Err.Msg != nil {
+			apiErr.Message = *e.Err.Msg
+		}
+		return typedAPIErr(resp, apiErr, e.Err.Path)
+	}
+	warns := parseWarnings(b)
+	if len(warns) == 0 {
+		return unparsedStatusErr(resp.StatusCode)
+	}
+	return APIWarn{Code: resp.StatusCode, Warnings: warns}
+}
+
+func parseWarnings(b []byte) []Warning {
 	w := &struct {
-		Warnings []struct {
-			Msg  *string `json:"warning_msg"`
-			Name *string `json:"warning_name"`
-		} `json:"warnings"`
+		Warnings []Warning `json:"warnings"`
 	}{}
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	if err := json.Unmarshal(b, w); err != nil {
+		return nil
 	}
-	err = json.Unmarshal(b, e)
-	if err != nil {
-		return err
+	return w.Warnings
+}
+
+// handleWarnings records the warnings of a successful response and fails in strict mode.
+// Callers return their result along with the error, see isWarning.
+func (c *hellosign) handleWarnings(resp *http.Response, b []byte) error {
+	warns := parseWarnings(b)
+	if len(warns) == 0 {
+		return nil
 	}
-	if e.Err.Name != nil {
-		return APIErr{Code: resp.StatusCode, Message: *e.Err.Msg, Name: *e.Err.Name}
+	if meta := responseMeta(resp.Request); meta != nil {
+		meta.Warnings = warns
+	}
+	if c.strictWarnings {
+		return APIWarn{Code: resp.StatusCode, Warnings: warns}
+	}
+	return nil
+}
+
+// isWarning reports whether err is the APIWarn of a successful response in strict mode. The call
+// took effect, so its result is returned along with err.
+func isWarning(err error) bool {
+	var warn APIWarn
+	return errors.As(err, &warn) && warn.Code >= 200 && warn.Code < 300
+}
+
+func (c *hellosign) parseResponse(resp *http.Response, dst interface{}) error {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newResponseErr(resp, nil, ErrUnexpectedStatus)
 	}
-	err = json.Unmarshal(b, w)
+	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
-	if len(w.Warnings) == 0 {
-		return errors.New("Could not parse response error or warning")
-	}
-	retErr := APIWarn{}
-	warns := []struct {
-		Name    string
-		Message string
-	}{}
-	for _, w := range w.Warnings {
-		warns = append(warns, struct {
-			Name    string
-			Message string
-		}{
-			Name:    *w.Name,
-			Message: *w.Msg,
-		})
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(dst); err != nil {
+		return err
 	}
-	return retErr
+	return c.handleWarnings(resp, b)
 }
 
-func (c *hellosign) parseResponse(resp *http.Response, dst interface{}) error {
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		d := json.NewDecoder(resp.Body)
-		d.UseNumber()
-		return d.Decode(dst)
+// closeBody closes the response body, reporting a failure to close unless err is already set.
+func closeBody(resp *http.Response, err *error) {
+	if cErr := resp.Body.Close(); *err == nil {
+		*err = cErr
 	}
-	return errors.New("Status code invalid")
 }
 
-func (c *hellosign) post(ept string, headers *map[string]string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPost, c.getEptURL(ept), body)
+func (c *hellosign) post(ctx context.Context, ept string, headers *map[string]string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.getEptURL(ept), body)
 	if err != nil {
 		return nil, err
 	}
@@ -207,39 +298,40 @@ func (c *hellosign) post(ept string, headers *map[string]string, body io.Reader)
 	return c.perform(req)
 }
 
-func (c *hellosign) postForm(ept string, o interface{}) (*http.Response, error) {
+func (c *hellosign) postForm(ctx context.Context, ept string, o interface{}) (*http.Response, error) {
 	b, w, err := c.marshalMultipart(o)
 	if err != nil {
 		return nil, err
 	}
-	return c.post(ept, &map[string]string{
+	// A bytes.Reader lets the request rewind the body when it is retried.
+	return c.post(ctx, ept, &map[string]string{
 		contentType: w.FormDataContentType(),
-	}, b)
+	}, bytes.NewReader(b.Bytes()))
 }
 
-func (c *hellosign) postFormAndParse(ept string, inp, dst interface{}) (err error) {
-	resp, err := c.postForm(ept, inp)
+func (c *hellosign) postFormAndParse(ctx context.Context, ept string, inp, dst interface{}) (err error) {
+	resp, err := c.postForm(ctx, ept, inp)
 	if err != nil {
 		return err
 	}
-	defer func() { err = resp.Body.Close() }()
+	defer closeBody(resp, &err)
 	return c.parseResponse(resp, dst)
 }
 
-func (c *hellosign) postEmptyExpect(ept string, expected int) (ok bool, err error) {
-	resp, err := c.post(ept, nil, nil)
+func (c *hellosign) postEmptyExpect(ctx context.Context, ept string, expected int) (ok bool, err error) {
+	resp, err := c.post(ctx, ept, nil, nil)
 	if err != nil {
 		return false, err
 	}
-	defer func() { err = resp.Body.Close() }()
+	defer closeBody(resp, &err)
 	if resp.StatusCode != expected {
-		return false, errors.New(resp.Status)
+		return false, newResponseErr(resp, nil, ErrUnexpectedStatus)
 	}
 	return true, nil
 }
 
-func (c *hellosign) delete(ept string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodDelete, c.getEptURL(ept), nil)
+func (c *hellosign) delete(ctx context.Context, ept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.getEptURL(ept), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -254,21 +346,22 @@ func BoolToInt(v bool) int8 {
 	return int8(1)
 }
 
-// GetEptURL returns the full HelloSign api url for a given endpoint.
+// GetEptURL returns the full HelloSign api url for a given endpoint using the default base url.
+// Clients configured with WithBaseURL resolve endpoints against their own base url instead.
 func GetEptURL(ept string) string {
 	return fmt.Sprintf("%s/%s", baseURL, ept)
 }
 
 func (c *hellosign) getEptURL(ept string) string {
-	return GetEptURL(ept)
+	return fmt.Sprintf("%s/%s", c.baseURL, ept)
 }
 
-func (c *hellosign) get(ept string, params *string) (*http.Response, error) {
+func (c *hellosign) get(ctx context.Context, ept string, params *string) (*http.Response, error) {
 	url := c.getEptURL(ept)
 	if params != nil && *params != "" {
 		url = fmt.Sprintf("%s?%s", url, *params)
 	}
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -276,16 +369,16 @@ func (c *hellosign) get(ept string, params *string) (*http.Response, error) {
 	return resp, err
 }
 
-func (c *hellosign) getAndParse(ept string, params *string, dst interface{}) (err error) {
-	resp, err := c.get(ept, params)
+func (c *hellosign) getAndParse(ctx context.Context, ept string, params *string, dst interface{}) (err error) {
+	resp, err := c.get(ctx, ept, params)
 	if err != nil {
 		return err
 	}
-	defer func() { err = resp.Body.Close() }()
+	defer closeBody(resp, &err)
 	return c.parseResponse(resp, dst)
 }
 
-func (c *hellosign) getFiles(ept, fileType string, getURL bool) (body []byte, fileURL *FileURL, err error) {
+func (c *hellosign) getFiles(ctx context.Context, ept, fileType string, getURL bool) (body []byte, fileURL *FileURL, err error) {
 	if fileType != "" && fileType != "pdf" && fileType != "zip" {
 		return []byte{}, nil, errors.New("Invalid file type specified, pdf or zip")
 	}
@@ -299,20 +392,21 @@ func (c *hellosign) getFiles(ept, fileType string, getURL bool) (body []byte, fi
 	if err != nil {
 		return []byte{}, nil, err
 	}
-	resp, err := c.get(ept, &parms)
+	resp, err := c.get(ctx, ept, &parms)
 	if err != nil {
 		return []byte{}, nil, err
 	}
-	defer func() { err = resp.Body.Close() }()
+	defer closeBody(resp, &err)
 	if resp.StatusCode != http.StatusOK {
-		return []byte{}, nil, errors.New(resp.Status)
+		return []byte{}, nil, newResponseErr(resp, nil, ErrUnexpectedStatus)
 	}
 	if getURL {
 		msg := &FileURL{}
-		if respErr := c.parseResponse(resp, msg); respErr != nil {
+		respErr := c.parseResponse(resp, msg)
+		if respErr != nil && !isWarning(respErr) {
 			return []byte{}, nil, respErr
 		}
-		return []byte{}, msg, nil
+		return []byte{}, msg, respErr
 	}
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -321,12 +415,12 @@ func (c *hellosign) getFiles(ept, fileType string, getURL bool) (body []byte, fi
 	return b, nil, nil
 }
 
-func (c *hellosign) list(ept string, parms ListParms, out interface{}) error {
+func (c *hellosign) list(ctx context.Context, ept string, parms ListParms, out interface{}) error {
 	paramString, err := form.EncodeToString(parms)
 	if err != nil {
 		return err
 	}
-	if err := c.getAndParse(ept, &paramString, out); err != nil {
+	if err := c.getAndParse(ctx, ept, &paramString, out); err != nil {
 		return err
 	}
 	return nil
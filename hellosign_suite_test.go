@@ -58,17 +58,17 @@ func TestHellosign(t *testing.T) {
 }
 
 func parseRequestParameters(req *http.Request) (map[string]string, error) {
-	var params map[string]string
+	fields := map[string]string{}
 	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
 	if err != nil {
-		return params, err
+		return fields, err
 	}
 	if !strings.HasPrefix(mediaType, "multipart/") {
-		return params, fmt.Errorf("invalid media type")
+		return fields, fmt.Errorf("invalid media type")
 	}
 	body, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		return params, err
+		return fields, err
 	}
 	mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
 	for {
@@ -77,14 +77,14 @@ func parseRequestParameters(req *http.Request) (map[string]string, error) {
 			break
 		}
 		if err != nil {
-			return params, err
+			return fields, err
 		}
 		key := p.FormName()
 		b, err := ioutil.ReadAll(p)
 		if err != nil {
-			return params, err
+			return fields, err
 		}
-		params[key] = string(b)
+		fields[key] = string(b)
 	}
-	return params, nil
+	return fields, nil
 }
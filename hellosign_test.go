@@ -1,11 +1,14 @@
 package hellosign
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/jarcoal/httpmock"
@@ -73,10 +76,7 @@ var _ = Describe("Hellosign", func() {
 
 	It("sets correct client values", func() {
 		Expect(client.apiKey).To(Equal(apiKey))
-		Expect(client.LastStatusCode).To(Equal(0))
-		Expect(client.RateLimit).To(Equal(uint64(0)))
-		Expect(client.RateLimitRemaining).To(Equal(uint64(0)))
-		Expect(client.RateLimitReset).To(Equal(uint64(0)))
+		Expect(client.RateLimitSnapshot()).To(Equal(RateLimit{}))
 	})
 
 	It("generates correct urls", func() {
@@ -112,11 +112,24 @@ var _ = Describe("Hellosign", func() {
 				resp.Header.Add(xRateLimitReset, "1")
 				return resp, nil
 			})
-		_, err := client.get("account", nil)
+		_, err := client.get(context.Background(), "account", nil)
 		Expect(err).To(BeNil())
-		Expect(client.RateLimit).To(Equal(uint64(3000)))
-		Expect(client.RateLimitRemaining).To(Equal(uint64(2999)))
-		Expect(client.RateLimitReset).To(Equal(uint64(1)))
+		rl := client.RateLimitSnapshot()
+		Expect(rl.Limit).To(Equal(uint64(3000)))
+		Expect(rl.Remaining).To(Equal(uint64(2999)))
+		Expect(rl.Reset).To(Equal(time.Unix(1, 0)))
+	})
+
+	It("passes the context to the http layer", func() {
+		httpmock.RegisterResponder(http.MethodGet, client.getEptURL("account"),
+			func(req *http.Request) (*http.Response, error) {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := client.get(ctx, "account", nil)
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
 	})
 
 	It("produces errors on non 2xx responses", func() {
@@ -125,8 +138,8 @@ var _ = Describe("Hellosign", func() {
 		req, _ := http.NewRequest(http.MethodGet, client.getEptURL("account"), nil)
 		_, err := client.perform(req)
 		Expect(err).ToNot(BeNil())
-		hErr, ok := err.(APIErr)
-		Expect(ok).To(BeTrue())
+		var hErr APIErr
+		Expect(errors.As(err, &hErr)).To(BeTrue())
 		Expect(hErr.Code).To(Equal(http.StatusBadRequest))
 		Expect(hErr.Message).To(Equal("Bad request"))
 		Expect(hErr.Name).To(Equal("bad_request"))
@@ -0,0 +1,182 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import "context"
+
+// pageFetcher fetches a single page of a list endpoint.
+type pageFetcher func(ctx context.Context, parms ListParms) (ListInfo, []interface{}, error)
+
+type pageResult struct {
+	items []interface{}
+	err   error
+}
+
+// ListIter walks all pages of a list endpoint. Pages are fetched as they are needed, subject to the
+// rate limit handling of the client. Call Close when stopping before Next returns false.
+type ListIter struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	fetch    pageFetcher
+	parms    ListParms
+	workers  int
+	started  bool
+	done     bool
+	nextPage uint64
+	numPages uint64
+	results  map[uint64]chan pageResult
+	buf      []interface{}
+	cur      interface{}
+	err      error
+}
+
+// ListOption configures a list iterator.
+type ListOption func(*ListIter)
+
+// WithListWorkers fetches up to n pages concurrently, ahead of the items being consumed.
+// Items are still returned in list order.
+func WithListWorkers(n int) ListOption {
+	return func(it *ListIter) {
+		it.workers = n
+	}
+}
+
+func newListIter(ctx context.Context, parms ListParms, fetch pageFetcher, opts []ListOption) *ListIter {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &ListIter{
+		ctx:     ctx,
+		cancel:  cancel,
+		fetch:   fetch,
+		parms:   parms,
+		workers: 1,
+	}
+	if it.parms.Page == 0 {
+		it.parms.Page = 1
+	}
+	for _, opt := range opts {
+		opt(it)
+	}
+	return it
+}
+
+// Next advances to the next item, fetching the next page when needed. It returns false when all
+// items have been returned or an error occurred.
+func (it *ListIter) Next() bool {
+	if it.done {
+		return false
+	}
+	for len(it.buf) == 0 {
+		if it.err != nil || (it.started && it.nextPage > it.numPages) {
+			it.Close()
+			return false
+		}
+		it.buf, it.err = it.fetchNext()
+	}
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ListIter) Err() error {
+	return it.err
+}
+
+// Close stops the iteration and any pages being fetched.
+func (it *ListIter) Close() {
+	it.done = true
+	it.buf = nil
+	it.cancel()
+}
+
+func (it *ListIter) fetchNext() ([]interface{}, error) {
+	if !it.started {
+		info, items, err := it.fetch(it.ctx, it.parms)
+		if err != nil {
+			return nil, err
+		}
+		it.started = true
+		it.nextPage = it.parms.Page + 1
+		it.numPages = info.NumPages
+		if it.workers > 1 && it.nextPage <= it.numPages {
+			it.startWorkers()
+		}
+		return items, nil
+	}
+	page := it.nextPage
+	it.nextPage++
+	if it.results == nil {
+		parms := it.parms
+		parms.Page = page
+		_, items, err := it.fetch(it.ctx, parms)
+		return items, err
+	}
+	select {
+	case res := <-it.results[page]:
+		return res.items, res.err
+	case <-it.ctx.Done():
+		return nil, it.ctx.Err()
+	}
+}
+
+// startWorkers fetches the remaining pages concurrently, each into its own buffered channel.
+func (it *ListIter) startWorkers() {
+	first, last := it.nextPage, it.numPages
+	it.results = make(map[uint64]chan pageResult, last-first+1)
+	pages := make(chan uint64)
+	for p := first; p <= last; p++ {
+		it.results[p] = make(chan pageResult, 1)
+	}
+	go func() {
+		defer close(pages)
+		for p := first; p <= last; p++ {
+			select {
+			case pages <- p:
+			case <-it.ctx.Done():
+				return
+			}
+		}
+	}()
+	results := it.results
+	for i := 0; i < it.workers; i++ {
+		parms := it.parms
+		go func() {
+			for p := range pages {
+				parms.Page = p
+				_, items, err := it.fetch(it.ctx, parms)
+				results[p] <- pageResult{items: items, err: err}
+			}
+		}()
+	}
+}
+
+// SigReqIter iterates over signature requests across all pages.
+type SigReqIter struct {
+	*ListIter
+}
+
+// Value returns the current signature request.
+func (it *SigReqIter) Value() SigReq {
+	return it.cur.(SigReq)
+}
+
+// TplIter iterates over templates across all pages.
+type TplIter struct {
+	*ListIter
+}
+
+// Value returns the current template.
+func (it *TplIter) Value() Tpl {
+	return it.cur.(Tpl)
+}
+
+// APIAppIter iterates over api apps across all pages.
+type APIAppIter struct {
+	*ListIter
+}
+
+// Value returns the current api app.
+func (it *APIAppIter) Value() APIApp {
+	return it.cur.(APIApp)
+}
@@ -0,0 +1,92 @@
+package hellosign_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListAll", func() {
+	const numPages = 4
+
+	var (
+		client   *hellosign.SignatureRequestAPI
+		requests int64
+		failPage int
+	)
+
+	_ = BeforeEach(func() {
+		client = hellosign.NewSignatureRequestAPI("asdf")
+		requests = 0
+		failPage = 0
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("signature_request/list"),
+			func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt64(&requests, 1)
+				page, err := strconv.Atoi(req.URL.Query().Get("page"))
+				Expect(err).To(BeNil())
+				Expect(req.URL.Query().Get("query")).To(Equal("complete:true"))
+				if page == failPage {
+					return httpmock.NewStringResponse(http.StatusInternalServerError, `{"error": {"error_msg": "failed", "error_name": "internal"}}`), nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{
+					"list_info": {"page": %d, "num_pages": %d, "num_results": %d, "page_size": 2},
+					"signature_requests": [{"signature_request_id": "%d-a"}, {"signature_request_id": "%d-b"}]
+				}`, page, numPages, numPages*2, page, page)), nil
+			})
+	})
+
+	collect := func(it *hellosign.SigReqIter) []string {
+		ids := []string{}
+		for it.Next() {
+			ids = append(ids, it.Value().SignatureRequestID)
+		}
+		return ids
+	}
+
+	expected := []string{"1-a", "1-b", "2-a", "2-b", "3-a", "3-b", "4-a", "4-b"}
+
+	It("walks all pages", func() {
+		it := client.ListAll(context.Background(), hellosign.ListParms{Query: "complete:true"})
+		Expect(collect(it)).To(Equal(expected))
+		Expect(it.Err()).To(BeNil())
+		Expect(requests).To(Equal(int64(numPages)))
+	})
+
+	It("starts at the given page", func() {
+		it := client.ListAll(context.Background(), hellosign.ListParms{Query: "complete:true", Page: 3})
+		Expect(collect(it)).To(Equal(expected[4:]))
+	})
+
+	It("fetches pages concurrently in order", func() {
+		it := client.ListAll(context.Background(), hellosign.ListParms{Query: "complete:true"}, hellosign.WithListWorkers(3))
+		Expect(collect(it)).To(Equal(expected))
+		Expect(it.Err()).To(BeNil())
+		Expect(atomic.LoadInt64(&requests)).To(Equal(int64(numPages)))
+	})
+
+	It("stops early when closed", func() {
+		it := client.ListAll(context.Background(), hellosign.ListParms{Query: "complete:true"})
+		Expect(it.Next()).To(BeTrue())
+		it.Close()
+		Expect(it.Next()).To(BeFalse())
+		Expect(it.Err()).To(BeNil())
+		Expect(requests).To(Equal(int64(1)))
+	})
+
+	It("stops at the first failing page", func() {
+		failPage = 3
+		it := client.ListAll(context.Background(), hellosign.ListParms{Query: "complete:true"}, hellosign.WithListWorkers(2))
+		Expect(collect(it)).To(Equal(expected[:4]))
+		var apiErr hellosign.APIErr
+		Expect(errors.As(it.Err(), &apiErr)).To(BeTrue())
+		Expect(apiErr.Code).To(Equal(http.StatusInternalServerError))
+	})
+})
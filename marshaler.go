@@ -6,11 +6,14 @@ package hellosign
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"mime/multipart"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func fieldTagName(tag string) string {
@@ -22,9 +25,21 @@ func fieldTagName(tag string) string {
 }
 
 func omitEmpty(tag string) bool {
+	return hasTagOption(tag, "omitempty")
+}
+
+// jsonEncoded reports whether the field is sent as a single json encoded value, for parameters the
+// api expects as json such as form fields.
+func jsonEncoded(tag string) bool {
+	return hasTagOption(tag, "json")
+}
+
+func hasTagOption(tag, option string) bool {
 	sArr := strings.Split(tag, ",")
-	if len(sArr) == 2 {
-		return sArr[1] == "omitempty"
+	for _, o := range sArr[1:] {
+		if o == option {
+			return true
+		}
 	}
 	return false
 }
@@ -39,104 +54,122 @@ func (c *hellosign) marshalMultipart(obj interface{}) (*bytes.Buffer, *multipart
 	return &b, w, nil
 }
 
+// marshalObj writes the tagged fields of the struct obj, or a pointer to it, as form fields. A nil
+// obj is an empty form.
 func marshalObj(w *multipart.Writer, prefix string, obj interface{}) error {
-	structType := reflect.TypeOf(obj)
+	if obj == nil {
+		return nil
+	}
 	val := reflect.ValueOf(obj)
 	if val.Kind() == reflect.Ptr {
 		if val.IsNil() {
 			return fmt.Errorf("cannot marshal nil ptr")
 		}
 		val = val.Elem()
-		structType = reflect.TypeOf(val.Interface())
 	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot marshal %s, expected a struct", val.Kind())
+	}
+	structType := val.Type()
 
 	for i := 0; i < val.NumField(); i++ {
-		valField := val.Field(i)
-		f := valField.Interface()
-		val := reflect.ValueOf(f)
-
 		field := structType.Field(i)
 		tag := field.Tag.Get("form")
 		tagName := fieldTagName(tag)
-		oe := omitEmpty(tag)
-
 		if tagName == "" || tagName == "-" {
 			continue
 		}
+		if jsonEncoded(tag) {
+			if err := marshalJSON(w, fieldKey(prefix, tagName), omitEmpty(tag), val.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := marshalValue(w, fieldKey(prefix, tagName), omitEmpty(tag), val.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		switch val.Kind() {
-		case reflect.Ptr:
-			if val.IsNil() {
-				continue
+// fieldKey returns the form key of a field, fields of nested objects are keyed prefix[name].
+func fieldKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return fmt.Sprintf("%s[%s]", prefix, name)
+}
+
+func marshalValue(w *multipart.Writer, key string, oe bool, val reflect.Value) error {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		return marshalValue(w, key, oe, val.Elem())
+	case reflect.Struct:
+		if t, ok := val.Interface().(time.Time); ok {
+			// Times are sent as unix timestamps.
+			if oe && t.IsZero() {
+				return nil
 			}
-			vInter := val.Interface()
-			if err := marshalObj(w, fmt.Sprintf("%s%s", prefix, tagName), vInter); err != nil {
+			return writeString(w, key, strconv.FormatInt(t.Unix(), 10))
+		}
+		return marshalObj(w, key, val.Interface())
+	case reflect.Map:
+		// Keys are sorted so requests are reproducible.
+		keys := val.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			if err := marshalValue(w, fmt.Sprintf("%s[%v]", key, k.Interface()), false, val.MapIndex(k)); err != nil {
 				return err
 			}
-		case reflect.Map:
-			if oe && len(val.MapKeys()) == 0 {
-				continue
-			}
-			for _, k := range val.MapKeys() {
-				if err := writeString(w, fmt.Sprintf("%s[%s]", prefix+tagName, k.String()), val.MapIndex(k).String()); err != nil {
+		}
+	case reflect.Slice:
+		for i := 0; i < val.Len(); i++ {
+			elemKey := fmt.Sprintf("%s[%d]", key, i)
+			elem := val.Index(i)
+			if elem.Kind() == reflect.Slice && elem.Type().Elem().Kind() == reflect.Uint8 {
+				// Byte slices are file contents.
+				ff, err := w.CreateFormFile(elemKey, fmt.Sprintf("Document %d", i))
+				if err != nil {
 					return err
 				}
-			}
-		case reflect.Slice:
-			if oe && val.Len() == 0 {
-				continue
-			}
-			fIndexVal := val.Index(0)
-			switch fIndexVal.Kind() {
-			case reflect.Slice:
-				if tagName == "file" {
-					for i := 0; i < val.Len(); i++ {
-						key := fmt.Sprintf("%s%s[%d]", prefix, tagName, i)
-						inter := val.Index(i).Interface()
-						bArr, ok := inter.([]byte)
-						if !ok {
-							return fmt.Errorf("%s is not a byte slice", key)
-						}
-						ff, err := w.CreateFormFile(key, fmt.Sprintf("Document %d", i))
-						if err != nil {
-							return err
-						}
-						ff.Write(bArr)
-					}
-				}
-				// No else case as we don't really have any other kinds of slices in slices.
-			case reflect.String:
-				for i := 0; i < val.Len(); i++ {
-					key := fmt.Sprintf("%s%s[%d]", prefix, tagName, i)
-					if err := writeString(w, key, val.Index(i).String()); err != nil {
-						return err
-					}
-				}
-			case reflect.Struct:
-				for i := 0; i < val.Len(); i++ {
-					sObj := val.Index(i).Interface()
-					key := fmt.Sprintf("%s%s[%d]", prefix, tagName, i)
-					if err := marshalObj(w, key, sObj); err != nil {
-						return err
-					}
+				if _, err := ff.Write(elem.Bytes()); err != nil {
+					return err
 				}
-
-			}
-
-		default:
-			key := fmt.Sprintf("%s%s", prefix, tagName)
-			if prefix != "" {
-				key = fmt.Sprintf("%s[%s]", prefix, tagName)
+				continue
 			}
-			if err := marshalPrimitive(w, oe, key, val.Interface()); err != nil {
+			if err := marshalValue(w, elemKey, false, elem); err != nil {
 				return err
 			}
 		}
-
+	default:
+		return marshalPrimitive(w, oe, key, val.Interface())
 	}
 	return nil
 }
 
+func marshalJSON(w *multipart.Writer, key string, oe bool, val reflect.Value) error {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+	case reflect.Map, reflect.Slice:
+		if val.IsNil() || (oe && val.Len() == 0) {
+			return nil
+		}
+	}
+	b, err := json.Marshal(val.Interface())
+	if err != nil {
+		return err
+	}
+	return writeString(w, key, string(b))
+}
+
 func marshalPrimitive(w *multipart.Writer, oe bool, tagName string, v interface{}) error {
 	val := reflect.ValueOf(v)
 	switch val.Kind() {
@@ -189,7 +222,7 @@ func marshalPrimitive(w *multipart.Writer, oe bool, tagName string, v interface{
 func writeString(w *multipart.Writer, name, val string) error {
 	ff, err := w.CreateFormField(name)
 	if err != nil {
-		return nil
+		return err
 	}
 	_, err = ff.Write([]byte(val))
 	return err
@@ -0,0 +1,126 @@
+package hellosign
+
+import (
+	"io/ioutil"
+	"mime/multipart"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Marshaler", func() {
+	type formPart struct {
+		Value    string
+		FileName string
+	}
+
+	// marshal encodes obj and returns the parts by form key, in the order they were written.
+	marshal := func(obj interface{}) ([]string, map[string]formPart, error) {
+		b, w, err := (&hellosign{}).marshalMultipart(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		r := multipart.NewReader(b, w.Boundary())
+		keys := []string{}
+		parts := map[string]formPart{}
+		for {
+			p, err := r.NextPart()
+			if err != nil {
+				break
+			}
+			v, err := ioutil.ReadAll(p)
+			Expect(err).To(BeNil())
+			keys = append(keys, p.FormName())
+			parts[p.FormName()] = formPart{Value: string(v), FileName: p.FileName()}
+		}
+		return keys, parts, nil
+	}
+
+	type signer struct {
+		Name  string  `form:"name"`
+		Order *uint64 `form:"order,omitempty"`
+	}
+
+	It("keys nested structs, slices and maps by their parents", func() {
+		order := uint64(2)
+		keys, parts, err := marshal(&struct {
+			Title    string            `form:"title"`
+			Signers  []signer          `form:"signers"`
+			Roles    map[string]signer `form:"roles"`
+			Metadata map[string]string `form:"metadata"`
+			Lead     *signer           `form:"lead"`
+			Skipped  string            `form:"-"`
+			Untagged string
+		}{
+			Title:    "Contract",
+			Signers:  []signer{{Name: "Jack", Order: &order}, {Name: "Jill"}},
+			Roles:    map[string]signer{"Witness": {Name: "Bob"}, "Client": {Name: "Alice"}},
+			Metadata: map[string]string{"b": "2", "a": "1"},
+			Lead:     &signer{Name: "Jack"},
+			Skipped:  "skipped",
+			Untagged: "untagged",
+		})
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]string{
+			"title",
+			"signers[0][name]",
+			"signers[0][order]",
+			"signers[1][name]",
+			"roles[Client][name]",
+			"roles[Witness][name]",
+			"metadata[a]",
+			"metadata[b]",
+			"lead[name]",
+		}))
+		Expect(parts["signers[0][order]"].Value).To(Equal("2"))
+		Expect(parts["roles[Client][name]"].Value).To(Equal("Alice"))
+		Expect(parts["metadata[b]"].Value).To(Equal("2"))
+	})
+
+	It("encodes primitives, times and empty values", func() {
+		at := time.Unix(1532640962, 0)
+		keys, parts, err := marshal(struct {
+			Flag       bool       `form:"flag"`
+			Unset      bool       `form:"unset,omitempty"`
+			Count      int8       `form:"count"`
+			Zero       uint64     `form:"zero,omitempty"`
+			Empty      []string   `form:"empty"`
+			NilPtr     *string    `form:"nil_ptr"`
+			ExpiresAt  *time.Time `form:"expires_at,omitempty"`
+			NotExpires time.Time  `form:"not_expires,omitempty"`
+		}{Flag: true, Count: -1, ExpiresAt: &at})
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]string{"flag", "count", "expires_at"}))
+		Expect(parts["flag"].Value).To(Equal("1"))
+		Expect(parts["count"].Value).To(Equal("-1"))
+		Expect(parts["expires_at"].Value).To(Equal("1532640962"))
+	})
+
+	It("sends byte slices as files and json tagged fields as json", func() {
+		_, parts, err := marshal(struct {
+			File   [][]byte          `form:"file"`
+			Fields [][]string        `form:"fields,omitempty,json"`
+			Groups map[string]string `form:"groups,omitempty,json"`
+		}{
+			File:   [][]byte{[]byte("%PDF-1"), []byte("%PDF-2")},
+			Fields: [][]string{{"a", "b"}},
+		})
+		Expect(err).To(BeNil())
+		Expect(parts["file[1]"]).To(Equal(formPart{Value: "%PDF-2", FileName: "Document 1"}))
+		Expect(parts["fields"].Value).To(Equal(`[["a","b"]]`))
+		Expect(parts).ToNot(HaveKey("groups"))
+	})
+
+	It("treats nil as an empty form and rejects other input", func() {
+		keys, _, err := marshal(nil)
+		Expect(err).To(BeNil())
+		Expect(keys).To(BeEmpty())
+
+		var nilPtr *signer
+		_, _, err = marshal(nilPtr)
+		Expect(err).ToNot(BeNil())
+		_, _, err = marshal("not a struct")
+		Expect(err).ToNot(BeNil())
+	})
+})
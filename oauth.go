@@ -0,0 +1,119 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const oauthURL = "https://app.hellosign.com/oauth"
+
+// OAuthAPI performs the OAuth flow of an api app to act on behalf of other HelloSign accounts.
+type OAuthAPI struct {
+	*hellosign
+	clientID     string
+	clientSecret string
+}
+
+// NewOAuthAPI creates a new client for the OAuth flow of the api app with the given client id and secret.
+func NewOAuthAPI(clientID, clientSecret string, opts ...Option) *OAuthAPI {
+	return &OAuthAPI{newHellosign("", opts...), clientID, clientSecret}
+}
+
+// OAuthToken is an access token for an account that authorized the api app.
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresIn    int64     `json:"expires_in"` // Seconds the token was valid for when issued
+	Expiry       time.Time `json:"expiry"`     // Set by the client from ExpiresIn
+}
+
+// Expired reports whether the token expires within the given margin.
+func (t *OAuthToken) Expired(margin time.Duration) bool {
+	return !t.Expiry.IsZero() && time.Now().Add(margin).After(t.Expiry)
+}
+
+// WithOAuthURL sets the url of the OAuth authorization and token endpoints.
+// Defaults to https://app.hellosign.com/oauth.
+func WithOAuthURL(oauthURL string) Option {
+	return func(c *hellosign) {
+		if oauthURL != "" {
+			c.oauthURL = strings.TrimRight(oauthURL, "/")
+		}
+	}
+}
+
+// NewOAuthClient creates a new api client for all HelloSign endpoints acting on behalf of the
+// account that granted the access token.
+func NewOAuthClient(accessToken string, opts ...Option) *Client {
+	tok := &OAuthToken{AccessToken: accessToken, TokenType: "Bearer"}
+	return NewClient("", append(opts, WithTokenSource(StaticTokenSource(tok)))...)
+}
+
+// AuthorizationURL returns the url to send users to for authorizing the api app. HelloSign
+// redirects back to the OAuth callback url of the app with a code and the state.
+func (c *OAuthAPI) AuthorizationURL(state string) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", c.clientID)
+	v.Set("state", state)
+	return fmt.Sprintf("%s/authorize?%s", c.oauthURL, v.Encode())
+}
+
+// ExchangeCode exchanges the code received on the OAuth callback url for an access token.
+func (c *OAuthAPI) ExchangeCode(code, state string) (*OAuthToken, error) {
+	return c.ExchangeCodeContext(context.Background(), code, state)
+}
+
+// ExchangeCodeContext is like ExchangeCode but uses ctx for the request.
+func (c *OAuthAPI) ExchangeCodeContext(ctx context.Context, code, state string) (*OAuthToken, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("state", state)
+	v.Set("client_id", c.clientID)
+	v.Set("client_secret", c.clientSecret)
+	return c.requestToken(ctx, "token", v)
+}
+
+// RefreshToken returns a new access token in exchange for the refresh token of an expired one.
+func (c *OAuthAPI) RefreshToken(refreshToken string) (*OAuthToken, error) {
+	return c.RefreshTokenContext(context.Background(), refreshToken)
+}
+
+// RefreshTokenContext is like RefreshToken but uses ctx for the request.
+func (c *OAuthAPI) RefreshTokenContext(ctx context.Context, refreshToken string) (*OAuthToken, error) {
+	v := url.Values{}
+	v.Set("grant_type", "refresh_token")
+	v.Set("refresh_token", refreshToken)
+	return c.requestToken(ctx, "token?refresh", v)
+}
+
+func (c *OAuthAPI) requestToken(ctx context.Context, ept string, v url.Values) (tok *OAuthToken, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s", c.oauthURL, ept), strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(contentType, "application/x-www-form-urlencoded")
+	resp, err := c.perform(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp, &err)
+	tok = &OAuthToken{}
+	if err = c.parseResponse(resp, tok); err != nil && !isWarning(err) {
+		return nil, err
+	}
+	if tok.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	}
+	return tok, err
+}
@@ -0,0 +1,98 @@
+package hellosign_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuth", func() {
+	const tokenURL = "https://app.hellosign.com/oauth/token"
+
+	var (
+		api *hellosign.OAuthAPI
+	)
+
+	_ = BeforeEach(func() {
+		api = hellosign.NewOAuthAPI("client-id", "secret")
+	})
+
+	It("builds the authorization url", func() {
+		u, err := url.Parse(api.AuthorizationURL("some state"))
+		Expect(err).To(BeNil())
+		Expect(u.Scheme + "://" + u.Host + u.Path).To(Equal("https://app.hellosign.com/oauth/authorize"))
+		Expect(u.Query().Get("response_type")).To(Equal("code"))
+		Expect(u.Query().Get("client_id")).To(Equal("client-id"))
+		Expect(u.Query().Get("state")).To(Equal("some state"))
+	})
+
+	It("exchanges codes for tokens", func() {
+		httpmock.RegisterResponder(http.MethodPost, tokenURL,
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("Authorization")).To(BeEmpty())
+				Expect(req.ParseForm()).To(Succeed())
+				Expect(req.PostForm.Get("grant_type")).To(Equal("authorization_code"))
+				Expect(req.PostForm.Get("code")).To(Equal("the-code"))
+				Expect(req.PostForm.Get("state")).To(Equal("the-state"))
+				Expect(req.PostForm.Get("client_id")).To(Equal("client-id"))
+				Expect(req.PostForm.Get("client_secret")).To(Equal("secret"))
+				return httpmock.NewStringResponse(http.StatusOK, `{
+					"access_token": "access",
+					"token_type": "Bearer",
+					"refresh_token": "refresh",
+					"expires_in": 86400,
+					"state": null
+				}`), nil
+			})
+		tok, err := api.ExchangeCode("the-code", "the-state")
+		Expect(err).To(BeNil())
+		Expect(tok.AccessToken).To(Equal("access"))
+		Expect(tok.TokenType).To(Equal("Bearer"))
+		Expect(tok.RefreshToken).To(Equal("refresh"))
+		Expect(tok.Expiry).To(BeTemporally("~", time.Now().Add(24*time.Hour), time.Minute))
+		Expect(tok.Expired(time.Minute)).To(BeFalse())
+		Expect(tok.Expired(25 * time.Hour)).To(BeTrue())
+	})
+
+	It("refreshes tokens", func() {
+		httpmock.RegisterResponder(http.MethodPost, tokenURL+"?refresh",
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.ParseForm()).To(Succeed())
+				Expect(req.PostForm.Get("grant_type")).To(Equal("refresh_token"))
+				Expect(req.PostForm.Get("refresh_token")).To(Equal("refresh"))
+				return httpmock.NewStringResponse(http.StatusOK, `{
+					"access_token": "new-access",
+					"token_type": "Bearer",
+					"refresh_token": "new-refresh",
+					"expires_in": 86400
+				}`), nil
+			})
+		tok, err := api.RefreshToken("refresh")
+		Expect(err).To(BeNil())
+		Expect(tok.AccessToken).To(Equal("new-access"))
+		Expect(tok.RefreshToken).To(Equal("new-refresh"))
+	})
+
+	It("returns token endpoint errors", func() {
+		httpmock.RegisterResponder(http.MethodPost, tokenURL,
+			httpmock.NewStringResponder(http.StatusUnauthorized, `{"error": {"error_msg": "Invalid code", "error_name": "unauthorized"}}`))
+		_, err := api.ExchangeCode("bad", "state")
+		Expect(err).To(MatchError(hellosign.ErrUnauthorized))
+	})
+
+	It("authenticates clients with bearer tokens", func() {
+		httpmock.RegisterResponder(http.MethodGet, "https://api.hellosign.com/v3/account",
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("Authorization")).To(Equal("Bearer access"))
+				return httpmock.NewStringResponse(http.StatusOK, `{"account": {"account_id": "1"}}`), nil
+			})
+		acc, err := hellosign.NewOAuthClient("access").Account.Get()
+		Expect(err).To(BeNil())
+		Expect(acc.AccountID).To(Equal("1"))
+	})
+})
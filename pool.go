@@ -0,0 +1,172 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ClientFactory creates the client of a tenant, for example with the api key or token source of
+// the tenant account.
+type ClientFactory func(ctx context.Context, tenantID string) (*Client, error)
+
+// ClientPool lazily creates and caches a client per tenant. Every client has its own rate limit
+// state, so with WithRateLimitWait a tenant that exhausted its limit does not hold up the others.
+// Clients not used for the idle timeout are evicted. It is safe for concurrent use.
+type ClientPool struct {
+	factory     ClientFactory
+	idleTimeout time.Duration
+	now         func() time.Time
+	mu          sync.Mutex
+	entries     map[string]*poolEntry
+	lastSweep   time.Time
+	stats       PoolStats
+}
+
+type poolEntry struct {
+	ready    chan struct{} // Closed once the client is created
+	client   *Client
+	err      error
+	lastUsed time.Time
+}
+
+// PoolStats are aggregate statistics of a ClientPool.
+type PoolStats struct {
+	Clients    int                  // Number of cached clients
+	Hits       uint64               // Calls to Get served from the cache
+	Created    uint64               // Clients created by the factory
+	Failed     uint64               // Factory calls that failed
+	Evicted    uint64               // Clients evicted, idle or explicitly
+	RateLimits map[string]RateLimit // Rate limit state by tenant id
+}
+
+// PoolOption configures a ClientPool.
+type PoolOption func(*ClientPool)
+
+// WithIdleTimeout sets how long a client is kept without being used. Defaults to 30 minutes,
+// zero keeps clients until they are evicted explicitly.
+func WithIdleTimeout(idleTimeout time.Duration) PoolOption {
+	return func(p *ClientPool) {
+		p.idleTimeout = idleTimeout
+	}
+}
+
+// NewClientPool creates a pool creating clients with factory.
+func NewClientPool(factory ClientFactory, opts ...PoolOption) *ClientPool {
+	p := &ClientPool{
+		factory:     factory,
+		idleTimeout: 30 * time.Minute,
+		now:         time.Now,
+		entries:     map[string]*poolEntry{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Get returns the client of the tenant, creating it when it is not cached. Concurrent calls for
+// the same tenant share one factory call, failed calls are not cached.
+func (p *ClientPool) Get(ctx context.Context, tenantID string) (*Client, error) {
+	p.mu.Lock()
+	now := p.now()
+	p.sweep(now)
+	e, ok := p.entries[tenantID]
+	if ok {
+		e.lastUsed = now
+		p.stats.Hits++
+		p.mu.Unlock()
+		select {
+		case <-e.ready:
+			return e.client, e.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	e = &poolEntry{ready: make(chan struct{}), lastUsed: now}
+	p.entries[tenantID] = e
+	p.mu.Unlock()
+
+	e.client, e.err = p.factory(ctx, tenantID)
+	p.mu.Lock()
+	if e.err != nil {
+		p.stats.Failed++
+		if p.entries[tenantID] == e {
+			delete(p.entries, tenantID)
+		}
+	} else {
+		p.stats.Created++
+	}
+	p.mu.Unlock()
+	close(e.ready)
+	return e.client, e.err
+}
+
+// Evict removes the client of the tenant from the pool.
+func (p *ClientPool) Evict(tenantID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.entries[tenantID]; ok {
+		delete(p.entries, tenantID)
+		p.stats.Evicted++
+	}
+}
+
+// EvictIdle removes the clients that have not been used for the idle timeout and returns how many
+// were removed. Get also evicts idle clients, at most once per idle timeout.
+func (p *ClientPool) EvictIdle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.evictIdle(p.now())
+}
+
+func (p *ClientPool) sweep(now time.Time) {
+	if p.idleTimeout > 0 && now.Sub(p.lastSweep) >= p.idleTimeout {
+		p.evictIdle(now)
+	}
+}
+
+func (p *ClientPool) evictIdle(now time.Time) int {
+	p.lastSweep = now
+	if p.idleTimeout <= 0 {
+		return 0
+	}
+	n := 0
+	for id, e := range p.entries {
+		if now.Sub(e.lastUsed) < p.idleTimeout {
+			continue
+		}
+		select {
+		case <-e.ready:
+			delete(p.entries, id)
+			n++
+		default:
+			// Still being created.
+		}
+	}
+	p.stats.Evicted += uint64(n)
+	return n
+}
+
+// Stats returns the current statistics of the pool.
+func (p *ClientPool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.RateLimits = map[string]RateLimit{}
+	for id, e := range p.entries {
+		select {
+		case <-e.ready:
+			if e.client != nil {
+				stats.RateLimits[id] = e.client.RateLimitSnapshot()
+				stats.Clients++
+			}
+		default:
+		}
+	}
+	return stats
+}
@@ -0,0 +1,116 @@
+package hellosign_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClientPool", func() {
+	var (
+		ctx     context.Context
+		created int32
+		factory hellosign.ClientFactory
+	)
+
+	_ = BeforeEach(func() {
+		ctx = context.Background()
+		atomic.StoreInt32(&created, 0)
+		factory = func(ctx context.Context, tenantID string) (*hellosign.Client, error) {
+			atomic.AddInt32(&created, 1)
+			if tenantID == "unknown" {
+				return nil, errors.New("unknown tenant")
+			}
+			return hellosign.NewClient("key-" + tenantID), nil
+		}
+	})
+
+	It("creates clients once per tenant", func() {
+		pool := hellosign.NewClientPool(factory)
+		var wg sync.WaitGroup
+		clients := make([]*hellosign.Client, 10)
+		for i := range clients {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				var err error
+				clients[i], err = pool.Get(ctx, "a")
+				Expect(err).To(BeNil())
+			}(i)
+		}
+		wg.Wait()
+		for _, c := range clients {
+			Expect(c).To(BeIdenticalTo(clients[0]))
+		}
+		b, err := pool.Get(ctx, "b")
+		Expect(err).To(BeNil())
+		Expect(b).ToNot(BeIdenticalTo(clients[0]))
+		Expect(atomic.LoadInt32(&created)).To(Equal(int32(2)))
+
+		stats := pool.Stats()
+		Expect(stats.Clients).To(Equal(2))
+		Expect(stats.Created).To(Equal(uint64(2)))
+		Expect(stats.Hits).To(Equal(uint64(9)))
+	})
+
+	It("does not cache factory errors", func() {
+		pool := hellosign.NewClientPool(factory)
+		_, err := pool.Get(ctx, "unknown")
+		Expect(err).To(MatchError("unknown tenant"))
+		_, err = pool.Get(ctx, "unknown")
+		Expect(err).ToNot(BeNil())
+		Expect(atomic.LoadInt32(&created)).To(Equal(int32(2)))
+		Expect(pool.Stats().Failed).To(Equal(uint64(2)))
+		Expect(pool.Stats().Clients).To(Equal(0))
+	})
+
+	It("evicts idle clients", func() {
+		pool := hellosign.NewClientPool(factory, hellosign.WithIdleTimeout(20*time.Millisecond))
+		a, err := pool.Get(ctx, "a")
+		Expect(err).To(BeNil())
+		time.Sleep(30 * time.Millisecond)
+		Expect(pool.EvictIdle()).To(Equal(1))
+		Expect(pool.Stats().Evicted).To(Equal(uint64(1)))
+		again, err := pool.Get(ctx, "a")
+		Expect(err).To(BeNil())
+		Expect(again).ToNot(BeIdenticalTo(a))
+
+		pool.Evict("a")
+		Expect(pool.Stats().Clients).To(Equal(0))
+		Expect(pool.Stats().Evicted).To(Equal(uint64(2)))
+	})
+
+	It("tracks rate limits per tenant", func() {
+		httpmock.RegisterResponder(http.MethodGet, "https://api.hellosign.com/v3/account",
+			func(req *http.Request) (*http.Response, error) {
+				user, _, _ := req.BasicAuth()
+				resp := httpmock.NewStringResponse(http.StatusOK, `{"account": {"account_id": "1"}}`)
+				resp.Header.Set("X-Ratelimit-Limit", "2000")
+				if user == "key-noisy" {
+					resp.Header.Set("X-Ratelimit-Limit-Remaining", "0")
+				} else {
+					resp.Header.Set("X-Ratelimit-Limit-Remaining", "1999")
+				}
+				return resp, nil
+			})
+		pool := hellosign.NewClientPool(factory)
+		for _, tenant := range []string{"noisy", "quiet"} {
+			c, err := pool.Get(ctx, tenant)
+			Expect(err).To(BeNil())
+			_, err = c.Account.Get()
+			Expect(err).To(BeNil())
+		}
+		limits := pool.Stats().RateLimits
+		Expect(limits["noisy"].Remaining).To(Equal(uint64(0)))
+		Expect(limits["quiet"].Remaining).To(Equal(uint64(1999)))
+	})
+})
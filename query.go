@@ -0,0 +1,193 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	queryAnd       = "AND"
+	queryOr        = "OR"
+	queryDateFmt   = "2006-01-02"
+	metadataPrefix = "metadata_"
+)
+
+// Query is a search query for the Query parameter of list endpoints. Queries are built from terms
+// such as Title or Complete, combined with And and Or, and turned into the search syntax by Build.
+//
+//	q := hellosign.Title("contract").And(hellosign.Signer("a@b.com").Or(hellosign.Signer("c@d.com")))
+//	parms := hellosign.ListParms{}
+//	parms.Query, err = q.Build(hellosign.SigReqQueryScope)
+type Query struct {
+	op    string // AND or OR for groups, empty for terms
+	terms []Query
+	field string
+	value string // Already escaped or formatted
+}
+
+// QueryScope holds the fields that a list endpoint can search.
+type QueryScope struct {
+	name     string
+	fields   map[string]bool
+	metadata bool
+}
+
+// Scopes for the search queries of the list endpoints.
+var (
+	SigReqQueryScope = QueryScope{
+		name:     "signature_request/list",
+		fields:   queryFields("title", "subject", "message", "from", "to", "signer", "cc", "complete", "declined", "created"),
+		metadata: true,
+	}
+	TplQueryScope = QueryScope{
+		name:     "template/list",
+		fields:   queryFields("title", "created"),
+		metadata: true,
+	}
+	APIAppQueryScope = QueryScope{
+		name:   "api_app/list",
+		fields: queryFields("name", "domain", "client_id", "created"),
+	}
+)
+
+func queryFields(fields ...string) map[string]bool {
+	m := map[string]bool{}
+	for _, f := range fields {
+		m[f] = true
+	}
+	return m
+}
+
+// Term matches items where field contains value.
+func Term(field, value string) Query {
+	return Query{field: field, value: escapeQueryValue(value)}
+}
+
+// Title matches items with a title containing value.
+func Title(value string) Query {
+	return Term("title", value)
+}
+
+// From matches signature requests sent from the given email address.
+func From(emailAddress string) Query {
+	return Term("from", emailAddress)
+}
+
+// To matches signature requests sent to the given email address.
+func To(emailAddress string) Query {
+	return Term("to", emailAddress)
+}
+
+// Signer matches signature requests with a signer with the given email address or name.
+func Signer(value string) Query {
+	return Term("signer", value)
+}
+
+// Complete matches signature requests that are, or are not, completely signed.
+func Complete(complete bool) Query {
+	return Query{field: "complete", value: strconv.FormatBool(complete)}
+}
+
+// Declined matches signature requests that are, or are not, declined.
+func Declined(declined bool) Query {
+	return Query{field: "declined", value: strconv.FormatBool(declined)}
+}
+
+// Metadata matches items with the metadata key set to value.
+func Metadata(key, value string) Query {
+	return Term(metadataPrefix+key, value)
+}
+
+// CreatedBetween matches items created in the given date range, inclusive. A zero time leaves
+// that end of the range open.
+func CreatedBetween(from, to time.Time) Query {
+	return Query{field: "created", value: fmt.Sprintf("[%s TO %s]", queryDate(from), queryDate(to))}
+}
+
+func queryDate(t time.Time) string {
+	if t.IsZero() {
+		return "*"
+	}
+	return t.Format(queryDateFmt)
+}
+
+// And matches items matching the query and all of the other queries.
+func (q Query) And(queries ...Query) Query {
+	return q.combine(queryAnd, queries)
+}
+
+// Or matches items matching the query or any of the other queries.
+func (q Query) Or(queries ...Query) Query {
+	return q.combine(queryOr, queries)
+}
+
+func (q Query) combine(op string, queries []Query) Query {
+	if q.op == op {
+		return Query{op: op, terms: append(append([]Query{}, q.terms...), queries...)}
+	}
+	return Query{op: op, terms: append([]Query{q}, queries...)}
+}
+
+// String returns the query in the search syntax without validating it.
+func (q Query) String() string {
+	if q.op == "" {
+		return fmt.Sprintf("%s:%s", q.field, q.value)
+	}
+	parts := []string{}
+	for _, t := range q.terms {
+		s := t.String()
+		if s == "" {
+			continue
+		}
+		if t.op != "" && t.op != q.op && len(t.terms) > 1 {
+			s = fmt.Sprintf("(%s)", s)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, fmt.Sprintf(" %s ", q.op))
+}
+
+// Validate checks that the query only uses fields supported by the list endpoint of scope.
+func (q Query) Validate(scope QueryScope) error {
+	if q.op != "" {
+		for _, t := range q.terms {
+			if err := t.Validate(scope); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if q.field == "" {
+		return fmt.Errorf("Query term without field for %s", scope.name)
+	}
+	if strings.HasPrefix(q.field, metadataPrefix) && scope.metadata {
+		return nil
+	}
+	if !scope.fields[q.field] {
+		return fmt.Errorf("Query field %s is not supported by %s", q.field, scope.name)
+	}
+	return nil
+}
+
+// Build validates the query against scope and returns it in the search syntax.
+func (q Query) Build(scope QueryScope) (string, error) {
+	if err := q.Validate(scope); err != nil {
+		return "", err
+	}
+	return q.String(), nil
+}
+
+// escapeQueryValue quotes values that contain whitespace, syntax characters or operators.
+func escapeQueryValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\n\":()[]{}\\*") && v != queryAnd && v != queryOr && v != "TO" {
+		return v
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return fmt.Sprintf(`"%s"`, r.Replace(v))
+}
@@ -0,0 +1,46 @@
+package hellosign_test
+
+import (
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query", func() {
+	It("builds terms", func() {
+		q, err := hellosign.Title("contract").Build(hellosign.SigReqQueryScope)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal("title:contract"))
+	})
+
+	It("combines terms", func() {
+		q, err := hellosign.Complete(true).
+			And(hellosign.Signer("a@b.com").Or(hellosign.Signer("c@d.com"))).
+			And(hellosign.Metadata("customer_id", "42")).
+			Build(hellosign.SigReqQueryScope)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal("complete:true AND (signer:a@b.com OR signer:c@d.com) AND metadata_customer_id:42"))
+	})
+
+	It("escapes values", func() {
+		Expect(hellosign.Title(`Employment "offer" letter`).String()).To(Equal(`title:"Employment \"offer\" letter"`))
+		Expect(hellosign.Title("OR").String()).To(Equal(`title:"OR"`))
+		Expect(hellosign.Title("").String()).To(Equal(`title:""`))
+	})
+
+	It("builds date ranges", func() {
+		from := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+		Expect(hellosign.CreatedBetween(from, time.Time{}).String()).To(Equal("created:[2016-01-01 TO *]"))
+	})
+
+	It("validates fields against the list endpoint", func() {
+		_, err := hellosign.Title("a").And(hellosign.Signer("b")).Build(hellosign.TplQueryScope)
+		Expect(err).ToNot(BeNil())
+		_, err = hellosign.Metadata("key", "value").Build(hellosign.APIAppQueryScope)
+		Expect(err).ToNot(BeNil())
+		_, err = hellosign.Term("domain", "example.com").Build(hellosign.APIAppQueryScope)
+		Expect(err).To(BeNil())
+	})
+})
@@ -0,0 +1,117 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const retryAfter = "retry-after"
+
+// RateLimit is a snapshot of the rate limit state last reported by the HelloSign API.
+type RateLimit struct {
+	Limit     uint64    // Number of requests allowed per hour
+	Remaining uint64    // Remaining number of requests this hour
+	Reset     time.Time // When the limit will be reset
+}
+
+// WithRateLimitWait makes the client wait for the rate limit to reset, instead of sending
+// requests that would be rejected, once no requests remain. Waiting respects the request context.
+func WithRateLimitWait() Option {
+	return func(c *hellosign) {
+		c.rateLimitWait = true
+	}
+}
+
+// rateLimiter tracks the rate limit reported in response headers.
+type rateLimiter struct {
+	mu           sync.Mutex
+	state        RateLimit
+	budget       uint64    // Remaining requests, counted down locally as requests are sent
+	known        bool      // The budget is known from a response
+	blockedUntil time.Time // Set from Retry-After on rate limited responses
+}
+
+// RateLimitSnapshot returns the rate limit state reported by the last response. It is safe for concurrent use.
+func (c *hellosign) RateLimitSnapshot() RateLimit {
+	c.rateLimiter.mu.Lock()
+	defer c.rateLimiter.mu.Unlock()
+	return c.rateLimiter.state
+}
+
+func (l *rateLimiter) update(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, hk := range []string{xRatelimitLimit, xRatelimitLimitRemaining, xRateLimitReset} {
+		hv := resp.Header.Get(hk)
+		if hv == "" {
+			continue
+		}
+		hvui, pErr := strconv.ParseUint(hv, 10, 64)
+		if pErr != nil {
+			continue
+		}
+		switch hk {
+		case xRatelimitLimit:
+			l.state.Limit = hvui
+		case xRatelimitLimitRemaining:
+			l.state.Remaining = hvui
+			l.budget = hvui
+			l.known = true
+		case xRateLimitReset:
+			l.state.Reset = time.Unix(int64(hvui), 0)
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if d, ok := parseRetryAfter(resp.Header.Get(retryAfter)); ok {
+			l.blockedUntil = time.Now().Add(d)
+		}
+	}
+}
+
+// wait blocks until a request may be sent without exceeding the rate limit.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		until := l.blockedUntil
+		if l.known && l.budget == 0 && l.state.Reset.After(until) {
+			until = l.state.Reset
+		}
+		if !until.After(now) {
+			if l.known && l.budget == 0 {
+				// The limit has been reset, the next response tells the new budget.
+				l.known = false
+			}
+			if l.known {
+				l.budget--
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+		if err := sleep(ctx, until.Sub(now)); err != nil {
+			return err
+		}
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an http date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseUint(v, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
@@ -0,0 +1,66 @@
+package hellosign_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimit", func() {
+	var (
+		requests int
+		reset    time.Time
+	)
+
+	_ = BeforeEach(func() {
+		requests = 0
+		reset = time.Now().Add(time.Hour).Truncate(time.Second)
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("team"),
+			func(req *http.Request) (*http.Response, error) {
+				requests++
+				resp := httpmock.NewStringResponse(http.StatusOK, `{"team": {"name": "team"}}`)
+				resp.Header.Set("X-Ratelimit-Limit", "2000")
+				resp.Header.Set("X-Ratelimit-Limit-Remaining", "0")
+				resp.Header.Set("X-Ratelimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+				return resp, nil
+			})
+	})
+
+	It("exposes the reported rate limit", func() {
+		client := hellosign.NewClient("asdf")
+		_, err := client.Team.Get()
+		Expect(err).To(BeNil())
+		Expect(client.RateLimitSnapshot()).To(Equal(hellosign.RateLimit{
+			Limit:     2000,
+			Remaining: 0,
+			Reset:     reset,
+		}))
+	})
+
+	It("waits for the rate limit to reset when enabled", func() {
+		client := hellosign.NewTeamAPI("asdf", hellosign.WithRateLimitWait())
+		_, err := client.Get()
+		Expect(err).To(BeNil())
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = client.GetContext(ctx)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(requests).To(Equal(1))
+	})
+
+	It("sends requests regardless of the rate limit by default", func() {
+		client := hellosign.NewTeamAPI("asdf")
+		_, err := client.Get()
+		Expect(err).To(BeNil())
+		_, err = client.Get()
+		Expect(err).To(BeNil())
+		Expect(requests).To(Equal(2))
+	})
+})
@@ -0,0 +1,43 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"context"
+	"net/http"
+)
+
+// ResponseMeta holds information about the response to a single api call.
+type ResponseMeta struct {
+	StatusCode int       // HTTP response code
+	RateLimit  RateLimit // Rate limit state after the call
+	Warnings   []Warning // Warnings returned along with a successful response
+}
+
+type responseMetaKey struct{}
+
+// ContextWithResponseMeta returns a copy of ctx that makes api calls made with it record information
+// about their response in meta. Use one meta per call, the Context variants of the api methods accept
+// the returned context.
+func ContextWithResponseMeta(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, meta)
+}
+
+func responseMeta(req *http.Request) *ResponseMeta {
+	if req == nil {
+		return nil
+	}
+	meta, _ := req.Context().Value(responseMetaKey{}).(*ResponseMeta)
+	return meta
+}
+
+func (c *hellosign) recordResponseMeta(req *http.Request, resp *http.Response) {
+	meta := responseMeta(req)
+	if meta == nil {
+		return
+	}
+	meta.StatusCode = resp.StatusCode
+	meta.RateLimit = c.RateLimitSnapshot()
+}
@@ -0,0 +1,175 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy decides whether and when a failed request is attempted again. Requests are only
+// retried when they fail with a transport error or a retryable status code.
+type RetryPolicy struct {
+	MaxAttempts          int                             // Total number of attempts including the first one. Values below 2 disable retries
+	MinBackoff           time.Duration                   // Wait before the second attempt
+	MaxBackoff           time.Duration                   // Upper bound of the wait between attempts
+	Jitter               float64                         // Fraction, between 0 and 1, of each wait that is randomized
+	Backoff              func(attempt int) time.Duration // Overrides the exponential backoff curve when set
+	RetryableStatusCodes []int                           // Response codes that are retried
+	RetryableErr         func(error) bool                // Decides which transport errors are retried. Defaults to connection errors
+	RetryPost            bool                            // Retry POST requests. These are not idempotent and only retried when set
+}
+
+// DefaultRetryPolicy retries idempotent requests on rate limit responses, server errors and connection failures.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+	Jitter:      0.2,
+	RetryableStatusCodes: []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// WithRetryPolicy enables retries of failed requests. Request bodies, including multipart forms,
+// are rewound so every attempt sends the full payload.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *hellosign) {
+		c.retryPolicy = policy
+	}
+}
+
+func (p RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		if !p.RetryPost {
+			return false
+		}
+	default:
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		// Cancellation and deadlines of the caller's context are final.
+		if req.Context().Err() != nil || errors.Is(err, context.Canceled) {
+			return false
+		}
+		if p.RetryableErr != nil {
+			return p.RetryableErr(err)
+		}
+		return isConnectionErr(err)
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait before the given attempt, the first retry being attempt 2.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	var d time.Duration
+	if p.Backoff != nil {
+		d = p.Backoff(attempt)
+	} else {
+		d = p.MinBackoff
+		for i := 2; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+			d *= 2
+		}
+		if p.MaxBackoff > 0 && d > p.MaxBackoff {
+			d = p.MaxBackoff
+		}
+	}
+	if p.Jitter > 0 && d > 0 {
+		d -= time.Duration(p.Jitter * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+// isConnectionErr reports whether err is a transient network failure. Errors such as invalid urls
+// and certificate verification failures are not.
+func isConnectionErr(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+	// Failing to connect is transient, the request was not sent yet.
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	// Every error of http.Client.Do is a net.Error, only timeouts are transient.
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// do sends the request, retrying it according to the retry policy of the client.
+func (c *hellosign) do(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		if c.rateLimitWait {
+			if err := c.rateLimiter.wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			c.rateLimiter.update(resp)
+		}
+		if attempt >= c.retryPolicy.MaxAttempts || !c.retryPolicy.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		wait := c.retryPolicy.backoff(attempt + 1)
+		if resp != nil {
+			if d, ok := parseRetryAfter(resp.Header.Get(retryAfter)); ok && d > wait {
+				wait = d
+			}
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,143 @@
+package hellosign_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry", func() {
+	var (
+		policy   hellosign.RetryPolicy
+		attempts int
+	)
+
+	_ = BeforeEach(func() {
+		policy = hellosign.DefaultRetryPolicy
+		policy.MinBackoff = time.Millisecond
+		policy.MaxBackoff = 5 * time.Millisecond
+		attempts = 0
+	})
+
+	It("retries idempotent requests on transient failures", func() {
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("team"),
+			func(req *http.Request) (*http.Response, error) {
+				attempts++
+				if attempts < 3 {
+					return httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, `{"team": {"name": "team"}}`), nil
+			})
+		client := hellosign.NewTeamAPI("asdf", hellosign.WithRetryPolicy(policy))
+		team, err := client.Get()
+		Expect(err).To(BeNil())
+		Expect(team.Name).To(Equal("team"))
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("gives up after the maximum number of attempts", func() {
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("team"),
+			func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return httpmock.NewStringResponse(http.StatusBadGateway, `{"error": {"error_msg": "down", "error_name": "bad_gateway"}}`), nil
+			})
+		client := hellosign.NewTeamAPI("asdf", hellosign.WithRetryPolicy(policy))
+		_, err := client.Get()
+		Expect(err).ToNot(BeNil())
+		Expect(attempts).To(Equal(policy.MaxAttempts))
+	})
+
+	It("retries connection failures but not permanent transport errors", func() {
+		client := hellosign.NewTeamAPI("asdf", hellosign.WithRetryPolicy(policy))
+		for _, connErr := range []error{
+			&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET},
+			&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+			&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("network is unreachable")},
+			&net.DNSError{Err: "server misbehaving", Name: "api.hellosign.com", IsTemporary: true},
+			io.ErrUnexpectedEOF,
+			timeoutErr{},
+		} {
+			connErr := connErr
+			attempts = 0
+			httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("team"),
+				func(req *http.Request) (*http.Response, error) {
+					attempts++
+					if attempts == 1 {
+						return nil, connErr
+					}
+					return httpmock.NewStringResponse(http.StatusOK, `{"team": {"name": "team"}}`), nil
+				})
+			_, err := client.Get()
+			Expect(err).To(BeNil())
+			Expect(attempts).To(Equal(2))
+		}
+
+		for _, transportErr := range []error{
+			x509.UnknownAuthorityError{},
+			errors.New("unsupported protocol scheme"),
+			&net.OpError{Op: "read", Net: "tcp", Err: errors.New("tls: bad record MAC")},
+			&net.DNSError{Err: "no such host", Name: "api.hellosign.com", IsNotFound: true},
+			context.Canceled,
+		} {
+			transportErr := transportErr
+			attempts = 0
+			httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("team"),
+				func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return nil, transportErr
+				})
+			_, err := client.Get()
+			Expect(err).ToNot(BeNil())
+			Expect(attempts).To(Equal(1))
+		}
+	})
+
+	It("does not retry posts unless enabled", func() {
+		httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL("team/create"),
+			func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return httpmock.NewStringResponse(http.StatusServiceUnavailable, `{"error": {"error_msg": "down", "error_name": "unavailable"}}`), nil
+			})
+		client := hellosign.NewTeamAPI("asdf", hellosign.WithRetryPolicy(policy))
+		_, err := client.Create("team")
+		Expect(err).ToNot(BeNil())
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("resends the full multipart body when retrying posts", func() {
+		httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL("team/create"),
+			func(req *http.Request) (*http.Response, error) {
+				attempts++
+				params, err := parseRequestParameters(req)
+				Expect(err).To(BeNil())
+				Expect(params["name"]).To(Equal("team"))
+				if attempts == 1 {
+					return httpmock.NewStringResponse(http.StatusTooManyRequests, ""), nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, `{"team": {"name": "team"}}`), nil
+			})
+		policy.RetryPost = true
+		client := hellosign.NewTeamAPI("asdf", hellosign.WithRetryPolicy(policy))
+		team, err := client.Create("team")
+		Expect(err).To(BeNil())
+		Expect(team.Name).To(Equal("team"))
+		Expect(attempts).To(Equal(2))
+	})
+})
+
+// timeoutErr is a net.Error that timed out.
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
@@ -1,4 +1,4 @@
 #!/bin/bash
 
-ginkgo -cover && go tool cover -html=hellosign.coverprofile
+ginkgo -r -race -cover && go tool cover -html=hellosign.coverprofile
 
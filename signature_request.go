@@ -5,11 +5,16 @@
 package hellosign
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // SignatureRequestAPI used for signature request manipulations.
@@ -18,8 +23,8 @@ type SignatureRequestAPI struct {
 }
 
 // NewSignatureRequestAPI creates a new api client for signature request manipulations.
-func NewSignatureRequestAPI(apiKey string) *SignatureRequestAPI {
-	return &SignatureRequestAPI{newHellosign(apiKey)}
+func NewSignatureRequestAPI(apiKey string, opts ...Option) *SignatureRequestAPI {
+	return &SignatureRequestAPI{newHellosign(apiKey, opts...)}
 }
 
 // SigReq contains information regarding documents that need to be signed.
@@ -53,8 +58,62 @@ type SigReq struct {
 		LastViewedAt       *uint64 `json:"last_viewed_at"`
 		LastRemindedAt     *uint64 `json:"last_reminded_at"`
 		HasPin             bool    `json:"has_pin"`
+		SignerRole         string  `json:"signer_role"` // Set for signature requests based on templates
 	} `json:"signatures"`
-	CCEmailAddresses []string `json:"cc_email_addresses"`
+	CCEmailAddresses []string           `json:"cc_email_addresses"`
+	BulkSendJobID    *string            `json:"bulk_send_job_id"`
+	Attachments      []SigReqAttachment `json:"attachments"`
+}
+
+// SigReqAttachment is a file a signer was asked to upload. Uploaded files are included when the
+// documents are downloaded as a zip file.
+type SigReqAttachment struct {
+	ID           string  `json:"id"`
+	Signer       string  `json:"signer"` // Signer index or email address
+	Name         string  `json:"name"`
+	Instructions string  `json:"instructions"`
+	Required     bool    `json:"required"`
+	UploadedAt   *uint64 `json:"uploaded_at"` // Nil until the signer uploaded the file
+}
+
+// UnmarshalJSON accepts the signer both as a number and as a string.
+func (a *SigReqAttachment) UnmarshalJSON(b []byte) error {
+	type attachment SigReqAttachment
+	raw := struct {
+		*attachment
+		Signer json.RawMessage `json:"signer"`
+	}{attachment: (*attachment)(a)}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	a.Signer = strings.Trim(string(raw.Signer), `"`)
+	if a.Signer == "null" {
+		a.Signer = ""
+	}
+	return nil
+}
+
+// SignerAttachments returns the attachments requested from the signer of the signature with the given id.
+// Attachments are matched by signer index, as given in SignerAttachment.SignerIndex, or by email address.
+// The signer index of a signature is its order, which the api returns for requests with a signing order.
+// Without one the signatures are assumed to be listed in the order the signers were given.
+func (s *SigReq) SignerAttachments(signatureID string) []SigReqAttachment {
+	attachments := []SigReqAttachment{}
+	for i, sig := range s.Signatures {
+		if sig.SignatureID != signatureID {
+			continue
+		}
+		index := strconv.Itoa(i)
+		if sig.Order != nil {
+			index = strconv.FormatUint(*sig.Order, 10)
+		}
+		for _, a := range s.Attachments {
+			if a.Signer != "" && (a.Signer == index || strings.EqualFold(a.Signer, sig.SignerEmailAddress)) {
+				attachments = append(attachments, a)
+			}
+		}
+	}
+	return attachments
 }
 
 type sigReqRaw struct {
@@ -63,8 +122,13 @@ type sigReqRaw struct {
 
 // Get returns the status of the SignatureRequest specified by the signatureRequestID parameter.
 func (c *SignatureRequestAPI) Get(signatureRequestID string) (*SigReq, error) {
+	return c.GetContext(context.Background(), signatureRequestID)
+}
+
+// GetContext is like Get but uses ctx for the request.
+func (c *SignatureRequestAPI) GetContext(ctx context.Context, signatureRequestID string) (*SigReq, error) {
 	sigReq := &sigReqRaw{}
-	err := c.getAndParse(fmt.Sprintf("signature_request/%s", signatureRequestID), nil, sigReq)
+	err := c.getAndParse(ctx, fmt.Sprintf("signature_request/%s", signatureRequestID), nil, sigReq)
 	return &sigReq.SigReq, err
 }
 
@@ -77,11 +141,31 @@ type SigReqLst struct {
 // List returns a list of SignatureRequests that you can access. This includes SignatureRequests
 // you have sent as well as received, but not ones that you have been CCed on.
 func (c *SignatureRequestAPI) List(parms ListParms) (*SigReqLst, error) {
+	return c.ListContext(context.Background(), parms)
+}
+
+// ListContext is like List but uses ctx for the request.
+func (c *SignatureRequestAPI) ListContext(ctx context.Context, parms ListParms) (*SigReqLst, error) {
 	lst := &SigReqLst{}
-	err := c.list("signature_request/list", parms, lst)
+	err := c.list(ctx, "signature_request/list", parms, lst)
 	return lst, err
 }
 
+// ListAll returns an iterator over the SignatureRequests of all pages, starting at parms.Page.
+func (c *SignatureRequestAPI) ListAll(ctx context.Context, parms ListParms, opts ...ListOption) *SigReqIter {
+	return &SigReqIter{newListIter(ctx, parms, func(ctx context.Context, parms ListParms) (ListInfo, []interface{}, error) {
+		lst, err := c.ListContext(ctx, parms)
+		if err != nil {
+			return ListInfo{}, nil, err
+		}
+		items := make([]interface{}, len(lst.SignatureRequests))
+		for i, v := range lst.SignatureRequests {
+			items[i] = v
+		}
+		return lst.ListInfo, items, nil
+	}, opts)}
+}
+
 // interface used for validation purposes.
 type sigReqFileParms interface {
 	hasFile() bool
@@ -91,22 +175,35 @@ type sigReqFileParms interface {
 
 // SigReqSendParms parameters for creating a new signature request.
 type SigReqSendParms struct {
-	Title                 string            `form:"title,omitempty"`
-	Subject               string            `form:"subject,omitempty"`
-	Message               string            `form:"message,omitempty"`
-	Metadata              map[string]string `form:"metadata,omitempty"`
-	TestMode              int8              `form:"test_mode,omitempty"`
-	AllowDecline          int8              `form:"allow_decline,omitempty"`
-	File                  [][]byte          `form:"file,omitempty"`
-	FileURL               []string          `form:"file_url,omitempty"`
-	FileIO                []io.Reader       `form:"-"`
-	Signers               []SigReqSigner    `form:"signers"`
-	CCEmailAddresses      []string          `form:"cc_email_addresses,omitempty"`
-	ClientID              string            `form:"client_id,omitempty"`
-	FormFieldsPerDocument string            `form:"form_fields_per_documents,omitempty"`
-	UseTextTags           int8              `form:"use_text_tags,omitempty"`
-	HideTextTags          int8              `form:"hide_text_tags,omitempty"`
-	SigningRedirectURL    string            `form:"message,omitempty"`
+	Title                 string                `form:"title,omitempty"`
+	Subject               string                `form:"subject,omitempty"`
+	Message               string                `form:"message,omitempty"`
+	Metadata              map[string]string     `form:"metadata,omitempty"`
+	TestMode              int8                  `form:"test_mode,omitempty"`
+	AllowDecline          int8                  `form:"allow_decline,omitempty"`
+	File                  [][]byte              `form:"file,omitempty"`
+	FileURL               []string              `form:"file_url,omitempty"`
+	FileIO                []io.Reader           `form:"-"`
+	Signers               []SigReqSigner        `form:"signers"`
+	CCEmailAddresses      []string              `form:"cc_email_addresses,omitempty"`
+	ClientID              string                `form:"client_id,omitempty"`
+	FormFieldsPerDocument FormFieldsPerDocument `form:"form_fields_per_document,omitempty,json"`
+	FormFieldGroups       []FormFieldGroup      `form:"form_field_groups,omitempty,json"`
+	UseTextTags           int8                  `form:"use_text_tags,omitempty"`
+	HideTextTags          int8                  `form:"hide_text_tags,omitempty"`
+	SigningRedirectURL    string                `form:"signing_redirect_url,omitempty"`
+	ExpiresAt             *time.Time            `form:"expires_at,omitempty"`
+	AllowReassign         int8                  `form:"allow_reassign,omitempty"`
+	AllowCcs              int8                  `form:"allow_ccs,omitempty"`
+	SigningOptions        *SigningOptions       `form:"signing_options,omitempty"`
+	FieldOptions          *FieldOptions         `form:"field_options,omitempty"`
+	Attachments           []SignerAttachment    `form:"attachments,omitempty"`
+	IsEID                 int8                  `form:"is_eid,omitempty"`
+	IsQualifiedSignature  int8                  `form:"is_qualified_signature,omitempty"`
+}
+
+func (c SigReqSendParms) options() sigReqOptions {
+	return sigReqOptions{c.ExpiresAt, c.SigningOptions, c.FieldOptions, c.Attachments, c.TestMode, c.IsEID, c.IsQualifiedSignature}
 }
 
 func (c SigReqSendParms) hasFile() bool {
@@ -153,7 +250,7 @@ func getFiles(fileIO []io.Reader) (*[][]byte, error) {
 	return &fileArr, nil
 }
 
-func (c *SignatureRequestAPI) validateSigReqFileParms(iParms interface{}) error {
+func (c *hellosign) validateSigReqFileParms(iParms interface{}) error {
 	parms, ok := iParms.(sigReqFileParms)
 	if !ok {
 		return errors.New("invalid input to validateSigReqFileParms")
@@ -178,36 +275,62 @@ func (c *SignatureRequestAPI) validateSigReqFileParms(iParms interface{}) error
 	return nil
 }
 
-// Send creates and sends a new SignatureRequest with the submitted documents. If FormFieldsPerDocument is
-// not specified, a signature page will be affixed where all signers will be required to add their signature,
-// signifying their agreement to all contained documents.
+// Send creates and sends a new SignatureRequest with the submitted documents. Signers are notified by email
+// and sign on HelloSign. If FormFieldsPerDocument is not specified, a signature page will be affixed where all
+// signers will be required to add their signature, signifying their agreement to all contained documents.
 func (c *SignatureRequestAPI) Send(parms SigReqSendParms) (*SigReq, error) {
+	return c.SendContext(context.Background(), parms)
+}
+
+// SendContext is like Send but uses ctx for the request.
+func (c *SignatureRequestAPI) SendContext(ctx context.Context, parms SigReqSendParms) (*SigReq, error) {
 	if err := c.validateSigReqFileParms(parms); err != nil {
 		return nil, err
 	}
-	(&parms).populateFile()
+	if err := validateFormFields(parms.FormFieldsPerDocument, parms.FormFieldGroups, len(parms.Signers), len(parms.File)+len(parms.FileURL)+len(parms.FileIO)); err != nil {
+		return nil, err
+	}
+	if err := validateSigReqOptions(parms.options(), len(parms.Signers)); err != nil {
+		return nil, err
+	}
+	if !(&parms).populateFile() {
+		return nil, errors.New("Could not read file io")
+	}
 	sigReq := &sigReqRaw{}
-	if err := c.postFormAndParse("signature_request/create_embedded", parms, sigReq); err != nil {
+	err := c.postFormAndParse(ctx, "signature_request/send", parms, sigReq)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &sigReq.SigReq, nil
+	return &sigReq.SigReq, err
 }
 
 // SigReqSendTplParms parameters for creating a signature request from a template.
 type SigReqSendTplParms struct {
-	Title              string                              `form:"title,omitempty"`
-	Subject            string                              `form:"subject,omitempty"`
-	Message            string                              `form:"message,omitempty"`
-	Metadata           map[string]string                   `form:"metadata,omitempty"`
-	TestMode           int8                                `form:"test_mode,omitempty"`
-	AllowDecline       int8                                `form:"allow_decline,omitempty"`
-	TemplateID         string                              `form:"template_id,omitempty"`
-	TemplateIds        []string                            `form:"template_ids,omitempty"`
-	SigningRedirectURL string                              `form:"signing_redirect_url,omitempty"`
-	Signers            map[string]SigReqSendTplParmsSigner `form:"signers"`
-	Ccs                map[string]SigReqSendTplParmsCcs    `form:"ccs,omitempty"`
-	CustomFields       string                              `form:"custom_fields,omitempty"`
-	ClientID           string                              `form:"client_id,omitempty"`
+	Title                string                              `form:"title,omitempty"`
+	Subject              string                              `form:"subject,omitempty"`
+	Message              string                              `form:"message,omitempty"`
+	Metadata             map[string]string                   `form:"metadata,omitempty"`
+	TestMode             int8                                `form:"test_mode,omitempty"`
+	AllowDecline         int8                                `form:"allow_decline,omitempty"`
+	TemplateID           string                              `form:"template_id,omitempty"`
+	TemplateIds          []string                            `form:"template_ids,omitempty"`
+	SigningRedirectURL   string                              `form:"signing_redirect_url,omitempty"`
+	Signers              map[string]SigReqSendTplParmsSigner `form:"signers"`
+	Ccs                  map[string]SigReqSendTplParmsCcs    `form:"ccs,omitempty"`
+	CustomFields         string                              `form:"custom_fields,omitempty"`
+	ClientID             string                              `form:"client_id,omitempty"`
+	ExpiresAt            *time.Time                          `form:"expires_at,omitempty"`
+	AllowReassign        int8                                `form:"allow_reassign,omitempty"`
+	AllowCcs             int8                                `form:"allow_ccs,omitempty"`
+	SigningOptions       *SigningOptions                     `form:"signing_options,omitempty"`
+	FieldOptions         *FieldOptions                       `form:"field_options,omitempty"`
+	Attachments          []SignerAttachment                  `form:"attachments,omitempty"`
+	IsEID                int8                                `form:"is_eid,omitempty"`
+	IsQualifiedSignature int8                                `form:"is_qualified_signature,omitempty"`
+}
+
+func (c SigReqSendTplParms) options() sigReqOptions {
+	return sigReqOptions{c.ExpiresAt, c.SigningOptions, c.FieldOptions, c.Attachments, c.TestMode, c.IsEID, c.IsQualifiedSignature}
 }
 
 // SigReqSendTplParmsSigner represents a person that should sign the template signature request.
@@ -225,49 +348,70 @@ type SigReqSendTplParmsCcs struct {
 
 // SendWithTemplate creates and sends a new SignatureRequest based off of the Template specified with the TemplateID parameter.
 func (c *SignatureRequestAPI) SendWithTemplate(parms SigReqSendTplParms) (*SigReq, error) {
+	return c.SendWithTemplateContext(context.Background(), parms)
+}
+
+// SendWithTemplateContext is like SendWithTemplate but uses ctx for the request.
+func (c *SignatureRequestAPI) SendWithTemplateContext(ctx context.Context, parms SigReqSendTplParms) (*SigReq, error) {
 	if parms.TemplateID == "" && len(parms.TemplateIds) == 0 {
 		return nil, errors.New("Specify either template id or template ids, none given")
 	}
 	if parms.TemplateID != "" && len(parms.TemplateIds) > 0 {
 		return nil, errors.New("Specify either template id or template ids, both given")
 	}
+	if err := validateSigReqOptions(parms.options(), len(parms.Signers)); err != nil {
+		return nil, err
+	}
 	sigReq := &sigReqRaw{}
-	if err := c.postFormAndParse("signature_request/send_with_template", &parms, sigReq); err != nil {
+	err := c.postFormAndParse(ctx, "signature_request/send_with_template", &parms, sigReq)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &sigReq.SigReq, nil
+	return &sigReq.SigReq, err
 }
 
 // SendReminder sends an email to the signer reminding them to sign the signature request. You cannot send a
 // reminder within 1 hour of the last reminder that was sent. This includes manual AND automatic reminders.
 func (c *SignatureRequestAPI) SendReminder(signatureRequestID, emailAddress string, name *string) (*SigReq, error) {
+	return c.SendReminderContext(context.Background(), signatureRequestID, emailAddress, name)
+}
+
+// SendReminderContext is like SendReminder but uses ctx for the request.
+func (c *SignatureRequestAPI) SendReminderContext(ctx context.Context, signatureRequestID, emailAddress string, name *string) (*SigReq, error) {
 	sigReq := &sigReqRaw{}
-	if err := c.postFormAndParse(fmt.Sprintf("signature_request/remind/%s", signatureRequestID), &struct {
+	err := c.postFormAndParse(ctx, fmt.Sprintf("signature_request/remind/%s", signatureRequestID), &struct {
 		EmailAddress string  `form:"email_address"`
 		Name         *string `form:"name,omitempty"`
 	}{
 		EmailAddress: emailAddress,
 		Name:         name,
-	}, sigReq); err != nil {
+	}, sigReq)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &sigReq.SigReq, nil
+	return &sigReq.SigReq, err
 }
 
 // Update updates the email address for a given signer on a signature request. You can listen for the
 // "signature_request_email_bounce" event on your app or account to detect bounced emails, and respond with this method.
 func (c *SignatureRequestAPI) Update(signatureRequestID, signatureID, email string) (*SigReq, error) {
+	return c.UpdateContext(context.Background(), signatureRequestID, signatureID, email)
+}
+
+// UpdateContext is like Update but uses ctx for the request.
+func (c *SignatureRequestAPI) UpdateContext(ctx context.Context, signatureRequestID, signatureID, email string) (*SigReq, error) {
 	sigReq := &sigReqRaw{}
-	if err := c.postFormAndParse(fmt.Sprintf("signature_request/update/%s", signatureRequestID), &struct {
+	err := c.postFormAndParse(ctx, fmt.Sprintf("signature_request/update/%s", signatureRequestID), &struct {
 		SignatureID  string `form:"signature_id"`
 		EmailAddress string `form:"email_address"`
 	}{
 		SignatureID:  signatureID,
 		EmailAddress: email,
-	}, sigReq); err != nil {
+	}, sigReq)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &sigReq.SigReq, nil
+	return &sigReq.SigReq, err
 }
 
 // Cancel Queues a SignatureRequest to be canceled. The cancelation is asynchronous and a successful call to this endpoint
@@ -279,7 +423,12 @@ func (c *SignatureRequestAPI) Update(signatureRequestID, signatureID, email stri
 // when the cancelation has taken place. If a callback handler has been configured and this event has not been received within
 // 60 minutes of making the call, please check the status of the request in the API Dashboard and retry the request if necessary.
 func (c *SignatureRequestAPI) Cancel(signatureRequestID string) (ok bool, err error) {
-	return c.postEmptyExpect(fmt.Sprintf("signature_request/cancel/%s", signatureRequestID), http.StatusOK)
+	return c.CancelContext(context.Background(), signatureRequestID)
+}
+
+// CancelContext is like Cancel but uses ctx for the request.
+func (c *SignatureRequestAPI) CancelContext(ctx context.Context, signatureRequestID string) (ok bool, err error) {
+	return c.postEmptyExpect(ctx, fmt.Sprintf("signature_request/cancel/%s", signatureRequestID), http.StatusOK)
 }
 
 // FileURL is an URL with an expiration time.
@@ -290,26 +439,44 @@ type FileURL struct {
 
 // Files obtain a copy of the current documents specified by the signatureRequestID parameter.
 func (c *SignatureRequestAPI) Files(signatureRequestID, fileType string, getURL bool) ([]byte, *FileURL, error) {
-	return c.getFiles(fmt.Sprintf("signature_request/files/%s", signatureRequestID), fileType, getURL)
+	return c.FilesContext(context.Background(), signatureRequestID, fileType, getURL)
+}
+
+// FilesContext is like Files but uses ctx for the request.
+func (c *SignatureRequestAPI) FilesContext(ctx context.Context, signatureRequestID, fileType string, getURL bool) ([]byte, *FileURL, error) {
+	return c.getFiles(ctx, fmt.Sprintf("signature_request/files/%s", signatureRequestID), fileType, getURL)
 }
 
 // SigReqEmbSendParms parameters for creating an embedded signature request.
 type SigReqEmbSendParms struct {
-	Title                 string            `form:"title,omitempty"`
-	Subject               string            `form:"subject,omitempty"`
-	Message               string            `form:"message,omitempty"`
-	Metadata              map[string]string `form:"metadata,omitempty"`
-	TestMode              int8              `form:"test_mode,omitempty"`
-	AllowDecline          int8              `form:"allow_decline,omitempty"`
-	File                  [][]byte          `form:"file,omitempty"`
-	FileURL               []string          `form:"file_url,omitempty"`
-	FileIO                []io.Reader       `form:"-"`
-	Signers               []SigReqSigner    `form:"signers"`
-	CCEmailAddresses      []string          `form:"cc_email_addresses,omitempty"`
-	ClientID              string            `form:"client_id,omitempty"`
-	FormFieldsPerDocument string            `form:"form_fields_per_documents,omitempty"`
-	UseTextTags           int8              `form:"use_text_tags,omitempty"`
-	HideTextTags          int8              `form:"hide_text_tags,omitempty"`
+	Title                 string                `form:"title,omitempty"`
+	Subject               string                `form:"subject,omitempty"`
+	Message               string                `form:"message,omitempty"`
+	Metadata              map[string]string     `form:"metadata,omitempty"`
+	TestMode              int8                  `form:"test_mode,omitempty"`
+	AllowDecline          int8                  `form:"allow_decline,omitempty"`
+	File                  [][]byte              `form:"file,omitempty"`
+	FileURL               []string              `form:"file_url,omitempty"`
+	FileIO                []io.Reader           `form:"-"`
+	Signers               []SigReqSigner        `form:"signers"`
+	CCEmailAddresses      []string              `form:"cc_email_addresses,omitempty"`
+	ClientID              string                `form:"client_id,omitempty"`
+	FormFieldsPerDocument FormFieldsPerDocument `form:"form_fields_per_document,omitempty,json"`
+	FormFieldGroups       []FormFieldGroup      `form:"form_field_groups,omitempty,json"`
+	UseTextTags           int8                  `form:"use_text_tags,omitempty"`
+	HideTextTags          int8                  `form:"hide_text_tags,omitempty"`
+	ExpiresAt             *time.Time            `form:"expires_at,omitempty"`
+	AllowReassign         int8                  `form:"allow_reassign,omitempty"`
+	AllowCcs              int8                  `form:"allow_ccs,omitempty"`
+	SigningOptions        *SigningOptions       `form:"signing_options,omitempty"`
+	FieldOptions          *FieldOptions         `form:"field_options,omitempty"`
+	Attachments           []SignerAttachment    `form:"attachments,omitempty"`
+	IsEID                 int8                  `form:"is_eid,omitempty"`
+	IsQualifiedSignature  int8                  `form:"is_qualified_signature,omitempty"`
+}
+
+func (c SigReqEmbSendParms) options() sigReqOptions {
+	return sigReqOptions{c.ExpiresAt, c.SigningOptions, c.FieldOptions, c.Attachments, c.TestMode, c.IsEID, c.IsQualifiedSignature}
 }
 
 func (c SigReqEmbSendParms) hasFile() bool {
@@ -341,13 +508,100 @@ func (c *SigReqEmbSendParms) populateFile() bool {
 // add their signature, signifying their agreement to all contained documents. Note that embedded signature requests
 // can only be signed in embedded iFrames whereas normal signature requests can only be signed on HelloSign.
 func (c *SignatureRequestAPI) SendEmbedded(parms SigReqEmbSendParms) (*SigReq, error) {
+	return c.SendEmbeddedContext(context.Background(), parms)
+}
+
+// SendEmbeddedContext is like SendEmbedded but uses ctx for the request.
+func (c *SignatureRequestAPI) SendEmbeddedContext(ctx context.Context, parms SigReqEmbSendParms) (*SigReq, error) {
 	if err := c.validateSigReqFileParms(parms); err != nil {
 		return nil, err
 	}
-	(&parms).populateFile()
+	if err := validateFormFields(parms.FormFieldsPerDocument, parms.FormFieldGroups, len(parms.Signers), len(parms.File)+len(parms.FileURL)+len(parms.FileIO)); err != nil {
+		return nil, err
+	}
+	if err := validateSigReqOptions(parms.options(), len(parms.Signers)); err != nil {
+		return nil, err
+	}
+	if !(&parms).populateFile() {
+		return nil, errors.New("Could not read file io")
+	}
 	sigReq := &sigReqRaw{}
-	if err := c.postFormAndParse("signature_request/create_embedded", parms, sigReq); err != nil {
+	err := c.postFormAndParse(ctx, "signature_request/create_embedded", parms, sigReq)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &sigReq.SigReq, nil
+	return &sigReq.SigReq, err
+}
+
+// SigReqEmbTplParms parameters for creating an embedded signature request from a template.
+type SigReqEmbTplParms struct {
+	ClientID     string                              `form:"client_id"`
+	Title        string                              `form:"title,omitempty"`
+	Subject      string                              `form:"subject,omitempty"`
+	Message      string                              `form:"message,omitempty"`
+	Metadata     map[string]string                   `form:"metadata,omitempty"`
+	TestMode     int8                                `form:"test_mode,omitempty"`
+	AllowDecline int8                                `form:"allow_decline,omitempty"`
+	TemplateID   string                              `form:"template_id,omitempty"`
+	TemplateIds  []string                            `form:"template_ids,omitempty"`
+	Signers      map[string]SigReqSendTplParmsSigner `form:"signers"`
+	Ccs          map[string]SigReqSendTplParmsCcs    `form:"ccs,omitempty"`
+	CustomFields string                              `form:"custom_fields,omitempty"`
+	Attachments  []SignerAttachment                  `form:"attachments,omitempty"`
+}
+
+// SendEmbeddedWithTemplate creates a new SignatureRequest based on the given Template to be signed in an
+// embedded iFrame. Note that embedded signature requests can only be signed in embedded iFrames whereas normal
+// signature requests can only be signed on HelloSign.
+func (c *SignatureRequestAPI) SendEmbeddedWithTemplate(parms SigReqEmbTplParms) (*SigReq, error) {
+	return c.SendEmbeddedWithTemplateContext(context.Background(), parms)
+}
+
+// SendEmbeddedWithTemplateContext is like SendEmbeddedWithTemplate but uses ctx for the request.
+func (c *SignatureRequestAPI) SendEmbeddedWithTemplateContext(ctx context.Context, parms SigReqEmbTplParms) (*SigReq, error) {
+	if parms.ClientID == "" {
+		return nil, errors.New("Specify client id")
+	}
+	if parms.TemplateID == "" && len(parms.TemplateIds) == 0 {
+		return nil, errors.New("Specify either template id or template ids, none given")
+	}
+	if parms.TemplateID != "" && len(parms.TemplateIds) > 0 {
+		return nil, errors.New("Specify either template id or template ids, both given")
+	}
+	if err := validateAttachments(parms.Attachments, len(parms.Signers)); err != nil {
+		return nil, err
+	}
+	sigReq := &sigReqRaw{}
+	err := c.postFormAndParse(ctx, "signature_request/create_embedded_with_template", &parms, sigReq)
+	if err != nil && !isWarning(err) {
+		return nil, err
+	}
+	return &sigReq.SigReq, err
+}
+
+// SendEmbeddedWithTemplateSignURLs creates an embedded signature request like SendEmbeddedWithTemplate and
+// retrieves the sign url of every signer, keyed by signer role.
+func (c *SignatureRequestAPI) SendEmbeddedWithTemplateSignURLs(parms SigReqEmbTplParms) (*SigReq, map[string]EmbeddedURL, error) {
+	return c.SendEmbeddedWithTemplateSignURLsContext(context.Background(), parms)
+}
+
+// SendEmbeddedWithTemplateSignURLsContext is like SendEmbeddedWithTemplateSignURLs but uses ctx for the requests.
+func (c *SignatureRequestAPI) SendEmbeddedWithTemplateSignURLsContext(ctx context.Context, parms SigReqEmbTplParms) (*SigReq, map[string]EmbeddedURL, error) {
+	sigReq, err := c.SendEmbeddedWithTemplateContext(ctx, parms)
+	if err != nil {
+		return nil, nil, err
+	}
+	embedded := &EmbeddedAPI{c.hellosign}
+	urls := map[string]EmbeddedURL{}
+	for _, sig := range sigReq.Signatures {
+		if sig.SignerRole == "" {
+			return sigReq, nil, fmt.Errorf("Signature %s has no signer role", sig.SignatureID)
+		}
+		url, err := embedded.GetSignURLContext(ctx, sig.SignatureID)
+		if err != nil {
+			return sigReq, nil, err
+		}
+		urls[sig.SignerRole] = *url
+	}
+	return sigReq, urls, nil
 }
@@ -0,0 +1,339 @@
+package hellosign_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SignatureRequestAPI", func() {
+	const sigReqResp = `{"signature_request": {"signature_request_id": "fa5c8a0b0f492d768749333ad6fcc214c111e967"}}`
+
+	var (
+		api    *hellosign.SignatureRequestAPI
+		params map[string]string
+	)
+
+	_ = BeforeEach(func() {
+		api = hellosign.NewSignatureRequestAPI("asdf")
+		params = nil
+	})
+
+	respond := func(ept string) {
+		httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL(ept),
+			func(req *http.Request) (*http.Response, error) {
+				var err error
+				params, err = parseRequestParameters(req)
+				Expect(err).To(BeNil())
+				return httpmock.NewStringResponse(http.StatusOK, sigReqResp), nil
+			})
+	}
+
+	keys := func() []string {
+		k := []string{}
+		for key := range params {
+			k = append(k, key)
+		}
+		sort.Strings(k)
+		return k
+	}
+
+	order := uint64(1)
+
+	// The field names emitted for each send mode, so changes to endpoints or tags are caught.
+	Describe("send modes", func() {
+		It("sends email signature requests", func() {
+			respond("signature_request/send")
+			sigReq, err := api.Send(hellosign.SigReqSendParms{
+				Title:        "Contract",
+				Subject:      "Please sign",
+				Message:      "Thanks",
+				Metadata:     map[string]string{"id": "1"},
+				TestMode:     1,
+				AllowDecline: 1,
+				FileURL:      []string{"https://example.com/a.pdf"},
+				Signers: []hellosign.SigReqSigner{
+					{Name: "Jack", EmailAddress: "jack@example.com", Order: &order, Pin: "1234"},
+				},
+				CCEmailAddresses: []string{"lawyer@example.com"},
+				ClientID:         "client-id",
+				FormFieldsPerDocument: hellosign.FormFieldsPerDocument{}.Add(0, hellosign.DocumentFormField{
+					APIID: "sig", Type: hellosign.FieldSignature, X: 10, Y: 20, Width: 120, Height: 30, Page: 1, Required: true,
+				}),
+				UseTextTags:        1,
+				HideTextTags:       1,
+				SigningRedirectURL: "https://example.com/signed",
+			})
+			Expect(err).To(BeNil())
+			Expect(sigReq.SignatureRequestID).To(Equal("fa5c8a0b0f492d768749333ad6fcc214c111e967"))
+			Expect(keys()).To(Equal([]string{
+				"allow_decline",
+				"cc_email_addresses[0]",
+				"client_id",
+				"file_url[0]",
+				"form_fields_per_document",
+				"hide_text_tags",
+				"message",
+				"metadata[id]",
+				"signers[0][email_address]",
+				"signers[0][name]",
+				"signers[0][order]",
+				"signers[0][pin]",
+				"signing_redirect_url",
+				"subject",
+				"test_mode",
+				"title",
+				"use_text_tags",
+			}))
+			Expect(params["message"]).To(Equal("Thanks"))
+			Expect(params["signing_redirect_url"]).To(Equal("https://example.com/signed"))
+			Expect(params["signers[0][order]"]).To(Equal("1"))
+		})
+
+		It("creates embedded signature requests", func() {
+			respond("signature_request/create_embedded")
+			_, err := api.SendEmbedded(hellosign.SigReqEmbSendParms{
+				Title:        "Contract",
+				Subject:      "Please sign",
+				Message:      "Thanks",
+				Metadata:     map[string]string{"id": "1"},
+				TestMode:     1,
+				AllowDecline: 1,
+				File:         [][]byte{[]byte("%PDF")},
+				Signers: []hellosign.SigReqSigner{
+					{Name: "Jack", EmailAddress: "jack@example.com"},
+				},
+				CCEmailAddresses: []string{"lawyer@example.com"},
+				ClientID:         "client-id",
+				FormFieldsPerDocument: hellosign.FormFieldsPerDocument{}.Add(0, hellosign.DocumentFormField{
+					APIID: "sig", Type: hellosign.FieldSignature, X: 10, Y: 20, Width: 120, Height: 30, Page: 1, Required: true,
+				}),
+				UseTextTags:  1,
+				HideTextTags: 1,
+			})
+			Expect(err).To(BeNil())
+			Expect(keys()).To(Equal([]string{
+				"allow_decline",
+				"cc_email_addresses[0]",
+				"client_id",
+				"file[0]",
+				"form_fields_per_document",
+				"hide_text_tags",
+				"message",
+				"metadata[id]",
+				"signers[0][email_address]",
+				"signers[0][name]",
+				"subject",
+				"test_mode",
+				"title",
+				"use_text_tags",
+			}))
+			Expect(params["file[0]"]).To(Equal("%PDF"))
+		})
+
+		It("sends signature requests with templates", func() {
+			respond("signature_request/send_with_template")
+			_, err := api.SendWithTemplate(hellosign.SigReqSendTplParms{
+				Title:              "Contract",
+				Subject:            "Please sign",
+				Message:            "Thanks",
+				Metadata:           map[string]string{"id": "1"},
+				TestMode:           1,
+				AllowDecline:       1,
+				TemplateID:         "tpl-1",
+				SigningRedirectURL: "https://example.com/signed",
+				Signers: map[string]hellosign.SigReqSendTplParmsSigner{
+					"Client": {Name: "Jack", EmailAddress: "jack@example.com", Pin: "1234"},
+				},
+				Ccs: map[string]hellosign.SigReqSendTplParmsCcs{
+					"Accounting": {EmailAddress: "accounting@example.com"},
+				},
+				CustomFields: `[{"name": "Cost", "value": "$20,000"}]`,
+				ClientID:     "client-id",
+			})
+			Expect(err).To(BeNil())
+			Expect(keys()).To(Equal([]string{
+				"allow_decline",
+				"ccs[Accounting][email_address]",
+				"client_id",
+				"custom_fields",
+				"message",
+				"metadata[id]",
+				"signers[Client][email_address]",
+				"signers[Client][name]",
+				"signers[Client][pin]",
+				"signing_redirect_url",
+				"subject",
+				"template_id",
+				"test_mode",
+				"title",
+			}))
+			Expect(params["signers[Client][name]"]).To(Equal("Jack"))
+		})
+
+		It("creates embedded signature requests with templates", func() {
+			respond("signature_request/create_embedded_with_template")
+			_, err := api.SendEmbeddedWithTemplate(hellosign.SigReqEmbTplParms{
+				ClientID:    "client-id",
+				Title:       "Contract",
+				TestMode:    1,
+				TemplateIds: []string{"tpl-1", "tpl-2"},
+				Signers: map[string]hellosign.SigReqSendTplParmsSigner{
+					"Client": {Name: "Jack", EmailAddress: "jack@example.com"},
+				},
+				Ccs: map[string]hellosign.SigReqSendTplParmsCcs{
+					"Accounting": {EmailAddress: "accounting@example.com"},
+				},
+				CustomFields: `[{"name": "Cost", "value": "$20,000"}]`,
+			})
+			Expect(err).To(BeNil())
+			Expect(keys()).To(Equal([]string{
+				"ccs[Accounting][email_address]",
+				"client_id",
+				"custom_fields",
+				"signers[Client][email_address]",
+				"signers[Client][name]",
+				"template_ids[0]",
+				"template_ids[1]",
+				"test_mode",
+				"title",
+			}))
+		})
+	})
+
+	It("fails when a file can not be read", func() {
+		posted := false
+		for _, ept := range []string{"signature_request/send", "signature_request/create_embedded"} {
+			httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL(ept),
+				func(req *http.Request) (*http.Response, error) {
+					posted = true
+					return httpmock.NewStringResponse(http.StatusOK, sigReqResp), nil
+				})
+		}
+		signers := []hellosign.SigReqSigner{{Name: "Jack", EmailAddress: "jack@example.com"}}
+		_, err := api.Send(hellosign.SigReqSendParms{FileIO: []io.Reader{failingReader{}}, Signers: signers})
+		Expect(err).ToNot(BeNil())
+		_, err = api.SendEmbedded(hellosign.SigReqEmbSendParms{FileIO: []io.Reader{failingReader{}}, Signers: signers, ClientID: "client-id"})
+		Expect(err).ToNot(BeNil())
+		Expect(posted).To(BeFalse())
+	})
+
+	It("validates embedded signature requests with templates", func() {
+		_, err := api.SendEmbeddedWithTemplate(hellosign.SigReqEmbTplParms{TemplateID: "tpl-1"})
+		Expect(err).ToNot(BeNil())
+		_, err = api.SendEmbeddedWithTemplate(hellosign.SigReqEmbTplParms{ClientID: "client-id"})
+		Expect(err).ToNot(BeNil())
+		_, err = api.SendEmbeddedWithTemplate(hellosign.SigReqEmbTplParms{ClientID: "client-id", TemplateID: "tpl-1", TemplateIds: []string{"tpl-2"}})
+		Expect(err).ToNot(BeNil())
+		_, err = api.SendEmbeddedWithTemplate(hellosign.SigReqEmbTplParms{
+			ClientID:    "client-id",
+			TemplateID:  "tpl-1",
+			Signers:     map[string]hellosign.SigReqSendTplParmsSigner{"Client": {Name: "Jack", EmailAddress: "jack@example.com"}},
+			Attachments: []hellosign.SignerAttachment{{Name: "Passport", SignerIndex: 1}},
+		})
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("links attachments to signers", func() {
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("signature_request/sig-req-1"),
+			httpmock.NewStringResponder(http.StatusOK, `{
+				"signature_request": {
+					"signature_request_id": "sig-req-1",
+					"signatures": [
+						{"signature_id": "sig-1", "signer_email_address": "jack@example.com"},
+						{"signature_id": "sig-2", "signer_email_address": "jill@example.com"}
+					],
+					"attachments": [
+						{"id": "att-1", "signer": 0, "name": "Passport", "required": true, "uploaded_at": 1532640962},
+						{"id": "att-2", "signer": "1", "name": "Passport", "instructions": "Photo page", "uploaded_at": null},
+						{"id": "att-3", "signer": "Jill@example.com", "name": "Proof of address"}
+					]
+				}
+			}`))
+		sigReq, err := api.Get("sig-req-1")
+		Expect(err).To(BeNil())
+		Expect(sigReq.Attachments).To(HaveLen(3))
+		Expect(sigReq.Attachments[0].Signer).To(Equal("0"))
+		Expect(*sigReq.Attachments[0].UploadedAt).To(Equal(uint64(1532640962)))
+		Expect(sigReq.Attachments[1].Instructions).To(Equal("Photo page"))
+		Expect(sigReq.Attachments[1].UploadedAt).To(BeNil())
+
+		ids := func(attachments []hellosign.SigReqAttachment) []string {
+			ids := []string{}
+			for _, a := range attachments {
+				ids = append(ids, a.ID)
+			}
+			return ids
+		}
+		Expect(ids(sigReq.SignerAttachments("sig-1"))).To(Equal([]string{"att-1"}))
+		Expect(ids(sigReq.SignerAttachments("sig-2"))).To(Equal([]string{"att-2", "att-3"}))
+		Expect(sigReq.SignerAttachments("sig-3")).To(BeEmpty())
+	})
+
+	It("links attachments to signers by signing order", func() {
+		httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("signature_request/sig-req-1"),
+			httpmock.NewStringResponder(http.StatusOK, `{
+				"signature_request": {
+					"signature_request_id": "sig-req-1",
+					"signatures": [
+						{"signature_id": "sig-2", "signer_email_address": "jill@example.com", "order": 1},
+						{"signature_id": "sig-1", "signer_email_address": "jack@example.com", "order": 0}
+					],
+					"attachments": [
+						{"id": "att-1", "signer": 0, "name": "Passport"},
+						{"id": "att-2", "signer": 1, "name": "Passport"}
+					]
+				}
+			}`))
+		sigReq, err := api.Get("sig-req-1")
+		Expect(err).To(BeNil())
+		Expect(sigReq.SignerAttachments("sig-1")).To(HaveLen(1))
+		Expect(sigReq.SignerAttachments("sig-1")[0].ID).To(Equal("att-1"))
+		Expect(sigReq.SignerAttachments("sig-2")).To(HaveLen(1))
+		Expect(sigReq.SignerAttachments("sig-2")[0].ID).To(Equal("att-2"))
+	})
+
+	It("returns sign urls by signer role", func() {
+		httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL("signature_request/create_embedded_with_template"),
+			httpmock.NewStringResponder(http.StatusOK, `{
+				"signature_request": {
+					"signature_request_id": "sig-req-1",
+					"signatures": [
+						{"signature_id": "sig-1", "signer_role": "Client", "signer_name": "Jack"},
+						{"signature_id": "sig-2", "signer_role": "Witness", "signer_name": "Jill"}
+					]
+				}
+			}`))
+		for _, id := range []string{"sig-1", "sig-2"} {
+			httpmock.RegisterResponder(http.MethodGet, hellosign.GetEptURL("embedded/sign_url/"+id),
+				httpmock.NewStringResponder(http.StatusOK, `{"embedded": {"sign_url": "https://app.hellosign.com/editor/embeddedSign?signature_id=`+id+`", "expires_at": 1414093891}}`))
+		}
+		sigReq, urls, err := api.SendEmbeddedWithTemplateSignURLs(hellosign.SigReqEmbTplParms{
+			ClientID:   "client-id",
+			TemplateID: "tpl-1",
+			Signers: map[string]hellosign.SigReqSendTplParmsSigner{
+				"Client":  {Name: "Jack", EmailAddress: "jack@example.com"},
+				"Witness": {Name: "Jill", EmailAddress: "jill@example.com"},
+			},
+		})
+		Expect(err).To(BeNil())
+		Expect(sigReq.Signatures[0].SignerRole).To(Equal("Client"))
+		Expect(urls).To(HaveLen(2))
+		Expect(urls["Client"].SignURL).To(HaveSuffix("signature_id=sig-1"))
+		Expect(urls["Witness"].SignURL).To(HaveSuffix("signature_id=sig-2"))
+		Expect(urls["Witness"].ExpiresAt).To(Equal(uint64(1414093891)))
+	})
+})
+
+// failingReader fails every read.
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("disk failure")
+}
@@ -0,0 +1,124 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Ways for signers to create their signature.
+const (
+	SigningTypeDraw   = "draw"
+	SigningTypeType   = "type"
+	SigningTypeUpload = "upload"
+	SigningTypePhone  = "phone"
+)
+
+// Date formats of date_signed fields.
+const (
+	DateFormatMonthDayYearSlash = "MM / DD / YYYY"
+	DateFormatMonthDayYearDash  = "MM - DD - YYYY"
+	DateFormatDayMonthYearSlash = "DD / MM / YYYY"
+	DateFormatDayMonthYearDash  = "DD - MM - YYYY"
+	DateFormatYearMonthDaySlash = "YYYY / MM / DD"
+	DateFormatYearMonthDayDash  = "YYYY - MM - DD"
+)
+
+var dateFormats = map[string]bool{
+	DateFormatMonthDayYearSlash: true,
+	DateFormatMonthDayYearDash:  true,
+	DateFormatDayMonthYearSlash: true,
+	DateFormatDayMonthYearDash:  true,
+	DateFormatYearMonthDaySlash: true,
+	DateFormatYearMonthDayDash:  true,
+}
+
+// SigningOptions sets the ways signers may create their signature and which of them is shown first.
+type SigningOptions struct {
+	Draw    bool   `form:"draw"`
+	Type    bool   `form:"type"`
+	Upload  bool   `form:"upload"`
+	Phone   bool   `form:"phone"`
+	Default string `form:"default"` // One of the enabled signing types
+}
+
+func (o SigningOptions) enabled(signingType string) bool {
+	switch signingType {
+	case SigningTypeDraw:
+		return o.Draw
+	case SigningTypeType:
+		return o.Type
+	case SigningTypeUpload:
+		return o.Upload
+	case SigningTypePhone:
+		return o.Phone
+	}
+	return false
+}
+
+// FieldOptions sets how fields are displayed to signers.
+type FieldOptions struct {
+	DateFormat string `form:"date_format"`
+}
+
+// SignerAttachment asks a signer to upload a file, for example a scan of an id card.
+type SignerAttachment struct {
+	Name         string `form:"name"`
+	Instructions string `form:"instructions,omitempty"`
+	SignerIndex  int    `form:"signer_index"` // Index of the signer
+	Required     bool   `form:"required"`
+}
+
+// sigReqOptions are the options shared by the ways of sending a signature request.
+type sigReqOptions struct {
+	ExpiresAt            *time.Time
+	SigningOptions       *SigningOptions
+	FieldOptions         *FieldOptions
+	Attachments          []SignerAttachment
+	TestMode             int8
+	IsEID                int8
+	IsQualifiedSignature int8
+}
+
+// validateSigReqOptions checks options that depend on each other or on the number of signers.
+func validateSigReqOptions(o sigReqOptions, numSigners int) error {
+	if o.ExpiresAt != nil && !o.ExpiresAt.After(time.Now()) {
+		return errors.New("Expiration must be in the future")
+	}
+	if o.SigningOptions != nil && !o.SigningOptions.enabled(o.SigningOptions.Default) {
+		return fmt.Errorf("Default signing type %q is not one of the enabled signing types", o.SigningOptions.Default)
+	}
+	if o.FieldOptions != nil && !dateFormats[o.FieldOptions.DateFormat] {
+		return fmt.Errorf("Invalid date format %q", o.FieldOptions.DateFormat)
+	}
+	if o.IsEID != 0 && o.IsQualifiedSignature != 0 {
+		return errors.New("Specify either eid or qualified signature, both given")
+	}
+	if o.IsEID != 0 || o.IsQualifiedSignature != 0 {
+		if o.TestMode != 0 {
+			return errors.New("Eid and qualified signatures cannot be used in test mode")
+		}
+		if numSigners != 1 {
+			return fmt.Errorf("Eid and qualified signatures require exactly one signer, %d given", numSigners)
+		}
+	}
+	return validateAttachments(o.Attachments, numSigners)
+}
+
+// validateAttachments checks that every attachment has a name and belongs to one of the signers.
+// Signer indices are not checked when there are no signers.
+func validateAttachments(attachments []SignerAttachment, numSigners int) error {
+	for i, a := range attachments {
+		if a.Name == "" {
+			return fmt.Errorf("Attachment %d: specify name", i)
+		}
+		if a.SignerIndex < 0 || (numSigners > 0 && a.SignerIndex >= numSigners) {
+			return fmt.Errorf("Attachment %s: signer index %d out of range, %d signers given", a.Name, a.SignerIndex, numSigners)
+		}
+	}
+	return nil
+}
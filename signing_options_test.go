@@ -0,0 +1,137 @@
+package hellosign_test
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signature request options", func() {
+	var (
+		api       *hellosign.SignatureRequestAPI
+		params    map[string]string
+		posted    bool
+		expiresAt time.Time
+	)
+
+	_ = BeforeEach(func() {
+		api = hellosign.NewSignatureRequestAPI("asdf")
+		params = nil
+		posted = false
+		expiresAt = time.Now().Add(30 * 24 * time.Hour)
+		for _, ept := range []string{"signature_request/send", "signature_request/create_embedded", "signature_request/send_with_template"} {
+			httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL(ept),
+				func(req *http.Request) (*http.Response, error) {
+					var err error
+					posted = true
+					params, err = parseRequestParameters(req)
+					Expect(err).To(BeNil())
+					return httpmock.NewStringResponse(http.StatusOK, `{"signature_request": {}}`), nil
+				})
+		}
+	})
+
+	signers := []hellosign.SigReqSigner{{Name: "Jack", EmailAddress: "jack@example.com"}}
+
+	It("sends expiration, permissions and signing options", func() {
+		_, err := api.Send(hellosign.SigReqSendParms{
+			FileURL:       []string{"https://example.com/a.pdf"},
+			Signers:       signers,
+			ExpiresAt:     &expiresAt,
+			AllowReassign: 1,
+			AllowCcs:      1,
+			SigningOptions: &hellosign.SigningOptions{
+				Draw: true, Type: true, Default: hellosign.SigningTypeType,
+			},
+			FieldOptions: &hellosign.FieldOptions{DateFormat: hellosign.DateFormatDayMonthYearSlash},
+			Attachments: []hellosign.SignerAttachment{
+				{Name: "Passport", Instructions: "A scan of the photo page", Required: true},
+			},
+			IsEID: 1,
+		})
+		Expect(err).To(BeNil())
+		Expect(params["expires_at"]).To(Equal(strconv.FormatInt(expiresAt.Unix(), 10)))
+		Expect(params["allow_reassign"]).To(Equal("1"))
+		Expect(params["allow_ccs"]).To(Equal("1"))
+		Expect(params["signing_options[draw]"]).To(Equal("1"))
+		Expect(params["signing_options[type]"]).To(Equal("1"))
+		Expect(params["signing_options[upload]"]).To(Equal("0"))
+		Expect(params["signing_options[phone]"]).To(Equal("0"))
+		Expect(params["signing_options[default]"]).To(Equal("type"))
+		Expect(params["field_options[date_format]"]).To(Equal("DD / MM / YYYY"))
+		Expect(params["attachments[0][name]"]).To(Equal("Passport"))
+		Expect(params["attachments[0][instructions]"]).To(Equal("A scan of the photo page"))
+		Expect(params["attachments[0][signer_index]"]).To(Equal("0"))
+		Expect(params["attachments[0][required]"]).To(Equal("1"))
+		Expect(params["is_eid"]).To(Equal("1"))
+		Expect(params).ToNot(HaveKey("is_qualified_signature"))
+	})
+
+	It("sends options of embedded and template signature requests", func() {
+		_, err := api.SendEmbedded(hellosign.SigReqEmbSendParms{
+			File:           [][]byte{[]byte("%PDF")},
+			Signers:        signers,
+			ClientID:       "client-id",
+			ExpiresAt:      &expiresAt,
+			SigningOptions: &hellosign.SigningOptions{Phone: true, Default: hellosign.SigningTypePhone},
+		})
+		Expect(err).To(BeNil())
+		Expect(params["expires_at"]).To(Equal(strconv.FormatInt(expiresAt.Unix(), 10)))
+		Expect(params["signing_options[default]"]).To(Equal("phone"))
+
+		_, err = api.SendWithTemplate(hellosign.SigReqSendTplParms{
+			TemplateID: "tpl-1",
+			Signers: map[string]hellosign.SigReqSendTplParmsSigner{
+				"Client": {Name: "Jack", EmailAddress: "jack@example.com"},
+			},
+			AllowReassign:        1,
+			FieldOptions:         &hellosign.FieldOptions{DateFormat: hellosign.DateFormatYearMonthDayDash},
+			IsQualifiedSignature: 1,
+		})
+		Expect(err).To(BeNil())
+		Expect(params["allow_reassign"]).To(Equal("1"))
+		Expect(params["field_options[date_format]"]).To(Equal("YYYY - MM - DD"))
+		Expect(params["is_qualified_signature"]).To(Equal("1"))
+		Expect(params).ToNot(HaveKey("expires_at"))
+	})
+
+	It("validates options before sending", func() {
+		past := time.Now().Add(-time.Hour)
+		twoSigners := append([]hellosign.SigReqSigner{{Name: "Jill", EmailAddress: "jill@example.com"}}, signers...)
+		for _, parms := range []hellosign.SigReqSendParms{
+			{Signers: signers, ExpiresAt: &past},
+			{Signers: signers, SigningOptions: &hellosign.SigningOptions{Draw: true}},
+			{Signers: signers, SigningOptions: &hellosign.SigningOptions{Draw: true, Default: hellosign.SigningTypeUpload}},
+			{Signers: signers, FieldOptions: &hellosign.FieldOptions{DateFormat: "YYYY-MM-DD"}},
+			{Signers: signers, IsEID: 1, IsQualifiedSignature: 1},
+			{Signers: signers, IsEID: 1, TestMode: 1},
+			{Signers: twoSigners, IsQualifiedSignature: 1},
+			{Signers: signers, Attachments: []hellosign.SignerAttachment{{Instructions: "A scan"}}},
+			{Signers: signers, Attachments: []hellosign.SignerAttachment{{Name: "Passport", SignerIndex: 1}}},
+		} {
+			parms.FileURL = []string{"https://example.com/a.pdf"}
+			_, err := api.Send(parms)
+			Expect(err).ToNot(BeNil())
+			_, err = api.SendEmbedded(hellosign.SigReqEmbSendParms{
+				FileURL:              parms.FileURL,
+				Signers:              parms.Signers,
+				TestMode:             parms.TestMode,
+				ExpiresAt:            parms.ExpiresAt,
+				SigningOptions:       parms.SigningOptions,
+				FieldOptions:         parms.FieldOptions,
+				Attachments:          parms.Attachments,
+				IsEID:                parms.IsEID,
+				IsQualifiedSignature: parms.IsQualifiedSignature,
+			})
+			Expect(err).ToNot(BeNil())
+		}
+		_, err := api.SendWithTemplate(hellosign.SigReqSendTplParms{TemplateID: "tpl-1", IsEID: 1})
+		Expect(err).ToNot(BeNil())
+		Expect(posted).To(BeFalse())
+	})
+})
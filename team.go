@@ -5,6 +5,7 @@
 package hellosign
 
 import (
+	"context"
 	"errors"
 	"net/http"
 )
@@ -15,8 +16,8 @@ type TeamAPI struct {
 }
 
 // NewTeamAPI creates a new api client for team endpoints.
-func NewTeamAPI(apiKey string) *TeamAPI {
-	return &TeamAPI{newHellosign(apiKey)}
+func NewTeamAPI(apiKey string, opts ...Option) *TeamAPI {
+	return &TeamAPI{newHellosign(apiKey, opts...)}
 }
 
 // Team contains information about your team and its members.
@@ -40,17 +41,28 @@ type teamRaw struct {
 // Get returns information about your Team as well as a list of its members. If you do not belong to a Team,
 // a 404 error with an error_name of "not_found" will be returned.
 func (c *TeamAPI) Get() (*Team, error) {
+	return c.GetContext(context.Background())
+}
+
+// GetContext is like Get but uses ctx for the request.
+func (c *TeamAPI) GetContext(ctx context.Context) (*Team, error) {
 	team := &teamRaw{}
-	if err := c.getAndParse("team", nil, team); err != nil {
+	err := c.getAndParse(ctx, "team", nil, team)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &team.Team, nil
+	return &team.Team, err
 }
 
 // Create creates a new Team and makes you a member. You must not currently belong to a Team to invoke.
 func (c *TeamAPI) Create(name string) (*Team, error) {
+	return c.CreateContext(context.Background(), name)
+}
+
+// CreateContext is like Create but uses ctx for the request.
+func (c *TeamAPI) CreateContext(ctx context.Context, name string) (*Team, error) {
 	team := &teamRaw{}
-	err := c.postFormAndParse("team/create", &struct {
+	err := c.postFormAndParse(ctx, "team/create", &struct {
 		Name string `form:"name"`
 	}{
 		Name: name,
@@ -60,26 +72,37 @@ func (c *TeamAPI) Create(name string) (*Team, error) {
 
 // Update updates the name of your Team.
 func (c *TeamAPI) Update(name string) (*Team, error) {
+	return c.UpdateContext(context.Background(), name)
+}
+
+// UpdateContext is like Update but uses ctx for the request.
+func (c *TeamAPI) UpdateContext(ctx context.Context, name string) (*Team, error) {
 	team := &teamRaw{}
-	if err := c.postFormAndParse("team", &struct {
+	err := c.postFormAndParse(ctx, "team", &struct {
 		Name string `form:"name"`
 	}{
 		Name: name,
-	}, team); err != nil {
+	}, team)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &team.Team, nil
+	return &team.Team, err
 }
 
 // Delete deletes your Team. Can only be invoked when you have a Team with only one member (yourself).
 func (c *TeamAPI) Delete() (ok bool, err error) {
-	resp, err := c.postForm("team/destroy", nil)
+	return c.DeleteContext(context.Background())
+}
+
+// DeleteContext is like Delete but uses ctx for the request.
+func (c *TeamAPI) DeleteContext(ctx context.Context) (ok bool, err error) {
+	resp, err := c.postForm(ctx, "team/destroy", nil)
 	if err != nil {
 		return false, err
 	}
-	defer func() { err = resp.Body.Close() }()
+	defer closeBody(resp, &err)
 	if resp.StatusCode != http.StatusOK {
-		return false, errors.New(resp.Status)
+		return false, newResponseErr(resp, nil, ErrUnexpectedStatus)
 	}
 	return true, nil
 }
@@ -89,12 +112,12 @@ type teamPostArgs struct {
 	EmailAddress *string `form:"email_address,omitempty"`
 }
 
-func (c *TeamAPI) addOrRemoveUser(ept string, accountID, emailAddress *string) (*Team, error) {
+func (c *TeamAPI) addOrRemoveUser(ctx context.Context, ept string, accountID, emailAddress *string) (*Team, error) {
 	if accountID != nil && emailAddress != nil {
 		return nil, errors.New("Specify either account id or email address, both given")
 	}
 	team := &teamRaw{}
-	err := c.postFormAndParse(ept, &teamPostArgs{
+	err := c.postFormAndParse(ctx, ept, &teamPostArgs{
 		AccountID:    accountID,
 		EmailAddress: emailAddress,
 	}, team)
@@ -106,10 +129,20 @@ func (c *TeamAPI) addOrRemoveUser(ept string, accountID, emailAddress *string) (
 // they will not automatically join the Team but instead will be sent an invitation to join. If a user is already a
 // part of another Team, a "team_invite_failed" error will be returned.
 func (c *TeamAPI) AddUser(accountID, emailAddress *string) (*Team, error) {
-	return c.addOrRemoveUser("team/add_member", accountID, emailAddress)
+	return c.AddUserContext(context.Background(), accountID, emailAddress)
+}
+
+// AddUserContext is like AddUser but uses ctx for the request.
+func (c *TeamAPI) AddUserContext(ctx context.Context, accountID, emailAddress *string) (*Team, error) {
+	return c.addOrRemoveUser(ctx, "team/add_member", accountID, emailAddress)
 }
 
 // RemoveUser removes a user from your Team. If the user had an outstanding invitation to your Team the invitation will be expired.
 func (c *TeamAPI) RemoveUser(accountID, emailAddress *string) (*Team, error) {
-	return c.addOrRemoveUser("team/remove_member", accountID, emailAddress)
+	return c.RemoveUserContext(context.Background(), accountID, emailAddress)
+}
+
+// RemoveUserContext is like RemoveUser but uses ctx for the request.
+func (c *TeamAPI) RemoveUserContext(ctx context.Context, accountID, emailAddress *string) (*Team, error) {
+	return c.addOrRemoveUser(ctx, "team/remove_member", accountID, emailAddress)
 }
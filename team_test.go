@@ -0,0 +1,41 @@
+package hellosign_test
+
+import (
+	"net/http"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Team", func() {
+	var client *hellosign.TeamAPI
+
+	_ = BeforeEach(func() {
+		client = hellosign.NewTeamAPI("asdf")
+	})
+
+	It("deletes the team", func() {
+		var params map[string]string
+		httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL("team/destroy"),
+			func(req *http.Request) (*http.Response, error) {
+				var err error
+				params, err = parseRequestParameters(req)
+				Expect(err).To(BeNil())
+				return httpmock.NewStringResponse(http.StatusOK, ""), nil
+			})
+		ok, err := client.Delete()
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(params).To(BeEmpty())
+	})
+
+	It("fails to delete the team on errors", func() {
+		httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL("team/destroy"),
+			httpmock.NewStringResponder(http.StatusForbidden, `{"error": {"error_msg": "Team has members", "error_name": "forbidden"}}`))
+		ok, err := client.Delete()
+		Expect(err).ToNot(BeNil())
+		Expect(ok).To(BeFalse())
+	})
+})
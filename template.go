@@ -5,6 +5,7 @@
 package hellosign
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,8 +17,8 @@ type TemplateAPI struct {
 }
 
 // NewTemplateAPI creates a new api client for template endpoints.
-func NewTemplateAPI(apiKey string) *TemplateAPI {
-	return &TemplateAPI{newHellosign(apiKey)}
+func NewTemplateAPI(apiKey string, opts ...Option) *TemplateAPI {
+	return &TemplateAPI{newHellosign(apiKey, opts...)}
 }
 
 // Tpl contains information about the templates you and your team have created
@@ -53,11 +54,17 @@ type tplRaw struct {
 
 // Get returns the Template specified by the id parameter.
 func (c *TemplateAPI) Get(templateID string) (*Tpl, error) {
+	return c.GetContext(context.Background(), templateID)
+}
+
+// GetContext is like Get but uses ctx for the request.
+func (c *TemplateAPI) GetContext(ctx context.Context, templateID string) (*Tpl, error) {
 	tpl := &tplRaw{}
-	if err := c.getAndParse(fmt.Sprintf("template/%s", templateID), nil, tpl); err != nil {
+	err := c.getAndParse(ctx, fmt.Sprintf("template/%s", templateID), nil, tpl)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &tpl.Template, nil
+	return &tpl.Template, err
 }
 
 // TplLst is a list of templates accessible by this account.
@@ -68,48 +75,89 @@ type TplLst struct {
 
 // List returns a list of the Templates that are accessible by you.
 func (c *TemplateAPI) List(parms ListParms) (*TplLst, error) {
+	return c.ListContext(context.Background(), parms)
+}
+
+// ListContext is like List but uses ctx for the request.
+func (c *TemplateAPI) ListContext(ctx context.Context, parms ListParms) (*TplLst, error) {
 	lst := &TplLst{}
-	err := c.list("template/list", parms, lst)
+	err := c.list(ctx, "template/list", parms, lst)
 	return lst, err
 }
 
+// ListAll returns an iterator over the Templates of all pages, starting at parms.Page.
+func (c *TemplateAPI) ListAll(ctx context.Context, parms ListParms, opts ...ListOption) *TplIter {
+	return &TplIter{newListIter(ctx, parms, func(ctx context.Context, parms ListParms) (ListInfo, []interface{}, error) {
+		lst, err := c.ListContext(ctx, parms)
+		if err != nil {
+			return ListInfo{}, nil, err
+		}
+		items := make([]interface{}, len(lst.Templates))
+		for i, v := range lst.Templates {
+			items[i] = v
+		}
+		return lst.ListInfo, items, nil
+	}, opts)}
+}
+
 type tplAddRemParms struct {
 	AccountID    *string `form:"account_id,omitempty"`
 	EmailAddress *string `form:"email_address,omitempty"`
 }
 
-func (c *TemplateAPI) addRemove(ept string, accountID, emailAddress *string) (*Tpl, error) {
+func (c *TemplateAPI) addRemove(ctx context.Context, ept string, accountID, emailAddress *string) (*Tpl, error) {
 	if accountID != nil && emailAddress != nil {
 		return nil, errors.New("Specify either account id or email address, both given")
 	}
 	tpl := &tplRaw{}
-	if err := c.postFormAndParse(ept, &tplAddRemParms{
+	err := c.postFormAndParse(ctx, ept, &tplAddRemParms{
 		AccountID:    accountID,
 		EmailAddress: emailAddress,
-	}, tpl); err != nil {
+	}, tpl)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &tpl.Template, nil
+	return &tpl.Template, err
 }
 
 // AddUser gives the specified Account access to the specified Template. The specified Account must be a part of your Team.
 func (c *TemplateAPI) AddUser(templateID string, accountID, emailAddress *string) (*Tpl, error) {
-	return c.addRemove(fmt.Sprintf("template/add_user/%s", templateID), accountID, emailAddress)
+	return c.AddUserContext(context.Background(), templateID, accountID, emailAddress)
+}
+
+// AddUserContext is like AddUser but uses ctx for the request.
+func (c *TemplateAPI) AddUserContext(ctx context.Context, templateID string, accountID, emailAddress *string) (*Tpl, error) {
+	return c.addRemove(ctx, fmt.Sprintf("template/add_user/%s", templateID), accountID, emailAddress)
 }
 
 // RemoveUser removes the specified Account's access to the specified Template.
 func (c *TemplateAPI) RemoveUser(templateID string, accountID, emailAddress *string) (*Tpl, error) {
-	return c.addRemove(fmt.Sprintf("template/remove_user/%s", templateID), accountID, emailAddress)
+	return c.RemoveUserContext(context.Background(), templateID, accountID, emailAddress)
+}
+
+// RemoveUserContext is like RemoveUser but uses ctx for the request.
+func (c *TemplateAPI) RemoveUserContext(ctx context.Context, templateID string, accountID, emailAddress *string) (*Tpl, error) {
+	return c.addRemove(ctx, fmt.Sprintf("template/remove_user/%s", templateID), accountID, emailAddress)
 }
 
 // Files obtain a copy of the original files specified by the template_id parameter.
 func (c *TemplateAPI) Files(templateID, fileType string, getURL bool) ([]byte, *FileURL, error) {
-	return c.getFiles(fmt.Sprintf("template/files/%s", templateID), fileType, getURL)
+	return c.FilesContext(context.Background(), templateID, fileType, getURL)
+}
+
+// FilesContext is like Files but uses ctx for the request.
+func (c *TemplateAPI) FilesContext(ctx context.Context, templateID, fileType string, getURL bool) ([]byte, *FileURL, error) {
+	return c.getFiles(ctx, fmt.Sprintf("template/files/%s", templateID), fileType, getURL)
 }
 
 // Delete completely deletes the template specified from the account.
 func (c *TemplateAPI) Delete(templateID string) (ok bool, err error) {
-	return c.postEmptyExpect(fmt.Sprintf("template/delete/%s", templateID), http.StatusOK)
+	return c.DeleteContext(context.Background(), templateID)
+}
+
+// DeleteContext is like Delete but uses ctx for the request.
+func (c *TemplateAPI) DeleteContext(ctx context.Context, templateID string) (ok bool, err error) {
+	return c.postEmptyExpect(ctx, fmt.Sprintf("template/delete/%s", templateID), http.StatusOK)
 }
 
 // TplEmbCreateParms parameters for creating template drafts.
@@ -142,6 +190,11 @@ type TplEmbMergeField struct {
 // CreateEmbeddedDraft he first step in an embedded template workflow. Creates a draft template
 // that can then be further set up in the template 'edit' stage.
 func (c *TemplateAPI) CreateEmbeddedDraft(parms TplEmbCreateParms) (*Tpl, error) {
+	return c.CreateEmbeddedDraftContext(context.Background(), parms)
+}
+
+// CreateEmbeddedDraftContext is like CreateEmbeddedDraft but uses ctx for the request.
+func (c *TemplateAPI) CreateEmbeddedDraftContext(ctx context.Context, parms TplEmbCreateParms) (*Tpl, error) {
 	if len(parms.File) == 0 && len(parms.FileURL) == 0 {
 		return nil, errors.New("Specify either file or file url, none given")
 	}
@@ -149,8 +202,9 @@ func (c *TemplateAPI) CreateEmbeddedDraft(parms TplEmbCreateParms) (*Tpl, error)
 		return nil, errors.New("Specify either file or file url, both given")
 	}
 	tpl := &tplRaw{}
-	if err := c.postFormAndParse("template/create_embedded_draft", parms, tpl); err != nil {
+	err := c.postFormAndParse(ctx, "template/create_embedded_draft", parms, tpl)
+	if err != nil && !isWarning(err) {
 		return nil, err
 	}
-	return &tpl.Template, nil
+	return &tpl.Template, err
 }
@@ -0,0 +1,248 @@
+// Copyright 2016 Precisely AB.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package hellosign
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrNoToken is returned by token sources that have no token to authenticate requests with.
+var ErrNoToken = errors.New("hellosign: no oauth token")
+
+// TokenSource provides the OAuth token that authenticates each request of a client.
+type TokenSource interface {
+	// Token returns a valid token.
+	Token(ctx context.Context) (*OAuthToken, error)
+}
+
+// TokenRefresher is implemented by token sources that can replace a token the api rejected. The
+// client refreshes the token and sends the request again once when a response is 401 Unauthorized.
+type TokenRefresher interface {
+	// Refresh returns a new token to use instead of the rejected one.
+	Refresh(ctx context.Context, rejected *OAuthToken) (*OAuthToken, error)
+}
+
+// WithTokenSource authenticates requests with the OAuth tokens of ts instead of the api key.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *hellosign) {
+		c.tokenSource = ts
+	}
+}
+
+type staticTokenSource struct {
+	tok *OAuthToken
+}
+
+// StaticTokenSource returns a token source that always returns tok.
+func StaticTokenSource(tok *OAuthToken) TokenSource {
+	return staticTokenSource{tok}
+}
+
+func (s staticTokenSource) Token(ctx context.Context) (*OAuthToken, error) {
+	return s.tok, nil
+}
+
+// RefreshingTokenSource returns the token kept in a TokenStore, refreshing it shortly before it
+// expires and when the api rejects it. Concurrent refreshes are serialized, requests waiting for a
+// refresh use the token it returns. Refreshed tokens are saved to the store.
+type RefreshingTokenSource struct {
+	oauth  *OAuthAPI
+	store  TokenStore
+	margin time.Duration
+	mu     sync.Mutex
+	tok    *OAuthToken
+}
+
+// RefreshingTokenSourceOption configures a RefreshingTokenSource.
+type RefreshingTokenSourceOption func(*RefreshingTokenSource)
+
+// WithRefreshMargin sets how long before it expires a token is refreshed. Defaults to one minute.
+func WithRefreshMargin(margin time.Duration) RefreshingTokenSourceOption {
+	return func(s *RefreshingTokenSource) {
+		s.margin = margin
+	}
+}
+
+// NewRefreshingTokenSource creates a token source for the token in store, refreshing it with oauth.
+func NewRefreshingTokenSource(oauth *OAuthAPI, store TokenStore, opts ...RefreshingTokenSourceOption) *RefreshingTokenSource {
+	s := &RefreshingTokenSource{
+		oauth:  oauth,
+		store:  store,
+		margin: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Token returns the stored token, refreshing it first when it is about to expire.
+func (s *RefreshingTokenSource) Token(ctx context.Context) (*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tok == nil {
+		tok, err := s.store.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if tok == nil {
+			return nil, ErrNoToken
+		}
+		s.tok = tok
+	}
+	if s.tok.Expired(s.margin) {
+		return s.refresh(ctx)
+	}
+	return s.tok, nil
+}
+
+// Refresh refreshes the rejected token. When it has already been replaced, the replacement is returned.
+func (s *RefreshingTokenSource) Refresh(ctx context.Context, rejected *OAuthToken) (*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tok != nil && rejected != nil && s.tok.AccessToken != rejected.AccessToken {
+		return s.tok, nil
+	}
+	if s.tok == nil {
+		s.tok = rejected
+	}
+	return s.refresh(ctx)
+}
+
+func (s *RefreshingTokenSource) refresh(ctx context.Context) (*OAuthToken, error) {
+	if s.tok == nil || s.tok.RefreshToken == "" {
+		return nil, ErrNoToken
+	}
+	tok, err := s.oauth.RefreshTokenContext(ctx, s.tok.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = s.tok.RefreshToken
+	}
+	if err := s.store.Save(ctx, tok); err != nil {
+		return nil, err
+	}
+	s.tok = tok
+	return tok, nil
+}
+
+// TokenStore persists the token of a RefreshingTokenSource.
+type TokenStore interface {
+	// Load returns the stored token, or nil when no token is stored.
+	Load(ctx context.Context) (*OAuthToken, error)
+	// Save replaces the stored token.
+	Save(ctx context.Context, tok *OAuthToken) error
+}
+
+// MemoryTokenStore keeps a token in memory.
+type MemoryTokenStore struct {
+	mu  sync.Mutex
+	tok *OAuthToken
+}
+
+// NewMemoryTokenStore creates a store holding tok, which may be nil.
+func NewMemoryTokenStore(tok *OAuthToken) *MemoryTokenStore {
+	return &MemoryTokenStore{tok: tok}
+}
+
+// Load returns the stored token.
+func (s *MemoryTokenStore) Load(ctx context.Context) (*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tok, nil
+}
+
+// Save replaces the stored token.
+func (s *MemoryTokenStore) Save(ctx context.Context, tok *OAuthToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tok = tok
+	return nil
+}
+
+// FileTokenStore keeps a token in a file encrypted with AES-GCM.
+type FileTokenStore struct {
+	path string
+	aead cipher.AEAD
+	mu   sync.Mutex
+}
+
+// NewFileTokenStore creates a store for the file at path, encrypted with an AES key of 16, 24 or 32 bytes.
+func NewFileTokenStore(path string, key []byte) (*FileTokenStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &FileTokenStore{path: path, aead: aead}, nil
+}
+
+// Load decrypts the stored token. It returns nil when the file does not exist.
+func (s *FileTokenStore) Load(ctx context.Context) (*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	n := s.aead.NonceSize()
+	if len(b) < n {
+		return nil, errors.New("hellosign: token file is truncated")
+	}
+	plain, err := s.aead.Open(nil, b[:n], b[n:], nil)
+	if err != nil {
+		return nil, err
+	}
+	tok := &OAuthToken{}
+	if err := json.Unmarshal(plain, tok); err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
+
+// Save encrypts the token and atomically replaces the file.
+func (s *FileTokenStore) Save(ctx context.Context, tok *OAuthToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	plain, err := json.Marshal(tok)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(s.aead.Seal(nonce, nonce, plain, nil)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
@@ -0,0 +1,167 @@
+package hellosign_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TokenSource", func() {
+	const (
+		accountURL = "https://api.hellosign.com/v3/account"
+		refreshURL = "https://app.hellosign.com/oauth/token?refresh"
+	)
+
+	var (
+		ctx       context.Context
+		refreshes int32
+		store     *hellosign.MemoryTokenStore
+		ts        *hellosign.RefreshingTokenSource
+	)
+
+	_ = BeforeEach(func() {
+		ctx = context.Background()
+		atomic.StoreInt32(&refreshes, 0)
+		httpmock.RegisterResponder(http.MethodPost, refreshURL,
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.ParseForm()).To(Succeed())
+				Expect(req.PostForm.Get("refresh_token")).To(Equal("refresh"))
+				atomic.AddInt32(&refreshes, 1)
+				return httpmock.NewStringResponse(http.StatusOK, `{
+					"access_token": "new-access",
+					"token_type": "Bearer",
+					"expires_in": 3600
+				}`), nil
+			})
+		httpmock.RegisterResponder(http.MethodGet, accountURL,
+			func(req *http.Request) (*http.Response, error) {
+				if req.Header.Get("Authorization") != "Bearer new-access" {
+					return httpmock.NewStringResponse(http.StatusUnauthorized, `{"error": {"error_msg": "Expired", "error_name": "unauthorized"}}`), nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, `{"account": {"account_id": "1"}}`), nil
+			})
+		store = hellosign.NewMemoryTokenStore(nil)
+		ts = hellosign.NewRefreshingTokenSource(hellosign.NewOAuthAPI("client-id", "secret"), store)
+	})
+
+	saveToken := func(expiry time.Time) {
+		Expect(store.Save(ctx, &hellosign.OAuthToken{
+			AccessToken:  "access",
+			RefreshToken: "refresh",
+			Expiry:       expiry,
+		})).To(Succeed())
+	}
+
+	It("fails without a stored token", func() {
+		_, err := ts.Token(ctx)
+		Expect(err).To(Equal(hellosign.ErrNoToken))
+	})
+
+	It("refreshes tokens before they expire", func() {
+		saveToken(time.Now().Add(30 * time.Second))
+		tok, err := ts.Token(ctx)
+		Expect(err).To(BeNil())
+		Expect(tok.AccessToken).To(Equal("new-access"))
+		Expect(tok.RefreshToken).To(Equal("refresh"))
+
+		stored, err := store.Load(ctx)
+		Expect(err).To(BeNil())
+		Expect(stored.AccessToken).To(Equal("new-access"))
+		Expect(stored.Expiry).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+	})
+
+	It("refreshes tokens rejected by the api and retries the request", func() {
+		saveToken(time.Now().Add(time.Hour))
+		client := hellosign.NewClient("", hellosign.WithTokenSource(ts))
+		acc, err := client.Account.Get()
+		Expect(err).To(BeNil())
+		Expect(acc.AccountID).To(Equal("1"))
+		Expect(atomic.LoadInt32(&refreshes)).To(Equal(int32(1)))
+	})
+
+	It("refreshes once for concurrent requests", func() {
+		saveToken(time.Now().Add(time.Hour))
+		client := hellosign.NewClient("", hellosign.WithTokenSource(ts))
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := client.Account.Get()
+				Expect(err).To(BeNil())
+			}()
+		}
+		wg.Wait()
+		Expect(atomic.LoadInt32(&refreshes)).To(Equal(int32(1)))
+	})
+
+	It("returns unauthorized errors when tokens can not be refreshed", func() {
+		client := hellosign.NewOAuthClient("access")
+		_, err := client.Account.Get()
+		Expect(err).To(MatchError(hellosign.ErrUnauthorized))
+		Expect(atomic.LoadInt32(&refreshes)).To(Equal(int32(0)))
+	})
+
+	Describe("FileTokenStore", func() {
+		var (
+			dir string
+			key []byte
+		)
+
+		_ = BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "hellosign")
+			Expect(err).To(BeNil())
+			key = bytes.Repeat([]byte{7}, 32)
+		})
+
+		_ = AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("stores tokens encrypted", func() {
+			path := filepath.Join(dir, "token")
+			fs, err := hellosign.NewFileTokenStore(path, key)
+			Expect(err).To(BeNil())
+			tok, err := fs.Load(ctx)
+			Expect(err).To(BeNil())
+			Expect(tok).To(BeNil())
+
+			expiry := time.Now().Add(time.Hour).Round(time.Second)
+			Expect(fs.Save(ctx, &hellosign.OAuthToken{AccessToken: "secret-access", RefreshToken: "refresh", Expiry: expiry})).To(Succeed())
+			b, err := ioutil.ReadFile(path)
+			Expect(err).To(BeNil())
+			Expect(string(b)).ToNot(ContainSubstring("secret-access"))
+
+			fs, err = hellosign.NewFileTokenStore(path, key)
+			Expect(err).To(BeNil())
+			tok, err = fs.Load(ctx)
+			Expect(err).To(BeNil())
+			Expect(tok.AccessToken).To(Equal("secret-access"))
+			Expect(tok.RefreshToken).To(Equal("refresh"))
+			Expect(tok.Expiry.Equal(expiry)).To(BeTrue())
+
+			fs, err = hellosign.NewFileTokenStore(path, bytes.Repeat([]byte{8}, 32))
+			Expect(err).To(BeNil())
+			_, err = fs.Load(ctx)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("rejects invalid keys", func() {
+			_, err := hellosign.NewFileTokenStore(filepath.Join(dir, "token"), []byte("short"))
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
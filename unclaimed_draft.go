@@ -4,10 +4,328 @@
 
 package hellosign
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Types of unclaimed drafts.
+const (
+	UnclaimedDraftSendDocument     = "send_document"     // A file the user sends for signing once claimed
+	UnclaimedDraftRequestSignature = "request_signature" // A signature request the user completes and sends once claimed
+)
+
+// UnclaimedDraftAPI used for unclaimed draft manipulations.
 type UnclaimedDraftAPI struct {
 	*hellosign
 }
 
-func NewUnclaimedDraftAPI(apiKey string) *UnclaimedDraftAPI {
-	return &UnclaimedDraftAPI{newHellosign(apiKey)}
+// NewUnclaimedDraftAPI creates a new api client for unclaimed draft operations.
+func NewUnclaimedDraftAPI(apiKey string, opts ...Option) *UnclaimedDraftAPI {
+	return &UnclaimedDraftAPI{newHellosign(apiKey, opts...)}
+}
+
+// UnclaimedDraft is a draft signature request that a user claims at the claim url to finish and send it.
+type UnclaimedDraft struct {
+	SignatureRequestID    *string `json:"signature_request_id"`
+	ClaimURL              string  `json:"claim_url"`
+	SigningRedirectURL    *string `json:"signing_redirect_url"`
+	RequestingRedirectURL *string `json:"requesting_redirect_url"`
+	ExpiresAt             *uint64 `json:"expires_at"`
+	TestMode              bool    `json:"test_mode"`
+}
+
+type unclaimedDraftRaw struct {
+	UnclaimedDraft UnclaimedDraft `json:"unclaimed_draft"`
+}
+
+// UnclaimedDraftCreateParms parameters for creating an unclaimed draft.
+type UnclaimedDraftCreateParms struct {
+	Type                  string                `form:"type"`
+	Subject               string                `form:"subject,omitempty"`
+	Message               string                `form:"message,omitempty"`
+	Metadata              map[string]string     `form:"metadata,omitempty"`
+	TestMode              int8                  `form:"test_mode,omitempty"`
+	AllowDecline          int8                  `form:"allow_decline,omitempty"`
+	File                  [][]byte              `form:"file,omitempty"`
+	FileURL               []string              `form:"file_url,omitempty"`
+	FileIO                []io.Reader           `form:"-"`
+	Signers               []SigReqSigner        `form:"signers,omitempty"`
+	CCEmailAddresses      []string              `form:"cc_email_addresses,omitempty"`
+	ClientID              string                `form:"client_id,omitempty"`
+	FormFieldsPerDocument FormFieldsPerDocument `form:"form_fields_per_document,omitempty,json"`
+	FormFieldGroups       []FormFieldGroup      `form:"form_field_groups,omitempty,json"`
+	UseTextTags           int8                  `form:"use_text_tags,omitempty"`
+	HideTextTags          int8                  `form:"hide_text_tags,omitempty"`
+	SigningRedirectURL    string                `form:"signing_redirect_url,omitempty"`
+	Attachments           []SignerAttachment    `form:"attachments,omitempty"`
+}
+
+func (c UnclaimedDraftCreateParms) hasFile() bool {
+	return len(c.File) > 0
+}
+
+func (c UnclaimedDraftCreateParms) hasFileURL() bool {
+	return len(c.FileURL) > 0
+}
+
+func (c UnclaimedDraftCreateParms) hasFileIO() bool {
+	return len(c.FileIO) > 0
+}
+
+func (c *UnclaimedDraftCreateParms) populateFile() bool {
+	if !c.hasFileIO() {
+		return true
+	}
+	arr, err := getFiles(c.FileIO)
+	if err != nil {
+		return false
+	}
+	c.File = *arr
+	return true
+}
+
+// UnclaimedDraftEmbCreateParms parameters for creating an unclaimed draft to be claimed in an embedded iFrame.
+type UnclaimedDraftEmbCreateParms struct {
+	ClientID              string                `form:"client_id"`
+	RequesterEmailAddress string                `form:"requester_email_address"`
+	Type                  string                `form:"type,omitempty"`
+	Subject               string                `form:"subject,omitempty"`
+	Message               string                `form:"message,omitempty"`
+	Metadata              map[string]string     `form:"metadata,omitempty"`
+	TestMode              int8                  `form:"test_mode,omitempty"`
+	AllowDecline          int8                  `form:"allow_decline,omitempty"`
+	File                  [][]byte              `form:"file,omitempty"`
+	FileURL               []string              `form:"file_url,omitempty"`
+	FileIO                []io.Reader           `form:"-"`
+	Signers               []SigReqSigner        `form:"signers,omitempty"`
+	CCEmailAddresses      []string              `form:"cc_email_addresses,omitempty"`
+	FormFieldsPerDocument FormFieldsPerDocument `form:"form_fields_per_document,omitempty,json"`
+	FormFieldGroups       []FormFieldGroup      `form:"form_field_groups,omitempty,json"`
+	UseTextTags           int8                  `form:"use_text_tags,omitempty"`
+	HideTextTags          int8                  `form:"hide_text_tags,omitempty"`
+	IsForEmbeddedSigning  int8                  `form:"is_for_embedded_signing,omitempty"`
+	SkipMeNow             int8                  `form:"skip_me_now,omitempty"`
+	SigningRedirectURL    string                `form:"signing_redirect_url,omitempty"`
+	RequestingRedirectURL string                `form:"requesting_redirect_url,omitempty"`
+	Attachments           []SignerAttachment    `form:"attachments,omitempty"`
+}
+
+func (c UnclaimedDraftEmbCreateParms) hasFile() bool {
+	return len(c.File) > 0
+}
+
+func (c UnclaimedDraftEmbCreateParms) hasFileURL() bool {
+	return len(c.FileURL) > 0
+}
+
+func (c UnclaimedDraftEmbCreateParms) hasFileIO() bool {
+	return len(c.FileIO) > 0
+}
+
+func (c *UnclaimedDraftEmbCreateParms) populateFile() bool {
+	if !c.hasFileIO() {
+		return true
+	}
+	arr, err := getFiles(c.FileIO)
+	if err != nil {
+		return false
+	}
+	c.File = *arr
+	return true
+}
+
+// UnclaimedDraftEmbTplParms parameters for creating an unclaimed draft from templates to be claimed
+// in an embedded iFrame. Files are optional and appended to the documents of the templates.
+type UnclaimedDraftEmbTplParms struct {
+	ClientID              string                              `form:"client_id"`
+	RequesterEmailAddress string                              `form:"requester_email_address"`
+	TemplateIds           []string                            `form:"template_ids"`
+	Title                 string                              `form:"title,omitempty"`
+	Subject               string                              `form:"subject,omitempty"`
+	Message               string                              `form:"message,omitempty"`
+	Metadata              map[string]string                   `form:"metadata,omitempty"`
+	TestMode              int8                                `form:"test_mode,omitempty"`
+	AllowDecline          int8                                `form:"allow_decline,omitempty"`
+	File                  [][]byte                            `form:"file,omitempty"`
+	FileURL               []string                            `form:"file_url,omitempty"`
+	FileIO                []io.Reader                         `form:"-"`
+	Signers               map[string]SigReqSendTplParmsSigner `form:"signers,omitempty"`
+	Ccs                   map[string]SigReqSendTplParmsCcs    `form:"ccs,omitempty"`
+	CustomFields          string                              `form:"custom_fields,omitempty"`
+	IsForEmbeddedSigning  int8                                `form:"is_for_embedded_signing,omitempty"`
+	SkipMeNow             int8                                `form:"skip_me_now,omitempty"`
+	SigningRedirectURL    string                              `form:"signing_redirect_url,omitempty"`
+	RequestingRedirectURL string                              `form:"requesting_redirect_url,omitempty"`
+	Attachments           []SignerAttachment                  `form:"attachments,omitempty"`
+}
+
+func (c UnclaimedDraftEmbTplParms) hasFile() bool {
+	return len(c.File) > 0
+}
+
+func (c UnclaimedDraftEmbTplParms) hasFileURL() bool {
+	return len(c.FileURL) > 0
+}
+
+func (c UnclaimedDraftEmbTplParms) hasFileIO() bool {
+	return len(c.FileIO) > 0
+}
+
+func (c *UnclaimedDraftEmbTplParms) populateFile() bool {
+	if !c.hasFileIO() {
+		return true
+	}
+	arr, err := getFiles(c.FileIO)
+	if err != nil {
+		return false
+	}
+	c.File = *arr
+	return true
+}
+
+// UnclaimedDraftEditParms parameters for editing and resending a signature request as an unclaimed draft.
+type UnclaimedDraftEditParms struct {
+	ClientID              string `form:"client_id"`
+	RequesterEmailAddress string `form:"requester_email_address,omitempty"`
+	TestMode              int8   `form:"test_mode,omitempty"`
+	IsForEmbeddedSigning  int8   `form:"is_for_embedded_signing,omitempty"`
+	SigningRedirectURL    string `form:"signing_redirect_url,omitempty"`
+	RequestingRedirectURL string `form:"requesting_redirect_url,omitempty"`
+}
+
+func validateUnclaimedDraftType(draftType string, signers []SigReqSigner) error {
+	switch draftType {
+	case UnclaimedDraftSendDocument:
+	case UnclaimedDraftRequestSignature:
+		if len(signers) == 0 {
+			return errors.New("Specify signers for request_signature drafts, none given")
+		}
+	default:
+		return fmt.Errorf("Invalid draft type %q, specify send_document or request_signature", draftType)
+	}
+	return nil
+}
+
+// Create creates a new draft that can be claimed using the claim url. The first authenticated user to
+// access the url claims the draft and is shown either the "Sign and send" or the "Request signature"
+// page with the draft loaded.
+func (c *UnclaimedDraftAPI) Create(parms UnclaimedDraftCreateParms) (*UnclaimedDraft, error) {
+	return c.CreateContext(context.Background(), parms)
+}
+
+// CreateContext is like Create but uses ctx for the request.
+func (c *UnclaimedDraftAPI) CreateContext(ctx context.Context, parms UnclaimedDraftCreateParms) (*UnclaimedDraft, error) {
+	if err := validateUnclaimedDraftType(parms.Type, parms.Signers); err != nil {
+		return nil, err
+	}
+	if err := c.validateSigReqFileParms(parms); err != nil {
+		return nil, err
+	}
+	if err := validateFormFields(parms.FormFieldsPerDocument, parms.FormFieldGroups, len(parms.Signers), len(parms.File)+len(parms.FileURL)+len(parms.FileIO)); err != nil {
+		return nil, err
+	}
+	if err := validateAttachments(parms.Attachments, len(parms.Signers)); err != nil {
+		return nil, err
+	}
+	if !(&parms).populateFile() {
+		return nil, errors.New("Could not read file io")
+	}
+	draft := &unclaimedDraftRaw{}
+	err := c.postFormAndParse(ctx, "unclaimed_draft/create", &parms, draft)
+	if err != nil && !isWarning(err) {
+		return nil, err
+	}
+	return &draft.UnclaimedDraft, err
+}
+
+// CreateEmbedded creates a new draft that can be claimed and used in an embedded iFrame. The first
+// authenticated user to access the url claims the draft, the requester email address is used when
+// no user is logged in.
+func (c *UnclaimedDraftAPI) CreateEmbedded(parms UnclaimedDraftEmbCreateParms) (*UnclaimedDraft, error) {
+	return c.CreateEmbeddedContext(context.Background(), parms)
+}
+
+// CreateEmbeddedContext is like CreateEmbedded but uses ctx for the request.
+func (c *UnclaimedDraftAPI) CreateEmbeddedContext(ctx context.Context, parms UnclaimedDraftEmbCreateParms) (*UnclaimedDraft, error) {
+	if parms.ClientID == "" || parms.RequesterEmailAddress == "" {
+		return nil, errors.New("Specify client id and requester email address")
+	}
+	if parms.Type != "" {
+		if err := validateUnclaimedDraftType(parms.Type, parms.Signers); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.validateSigReqFileParms(parms); err != nil {
+		return nil, err
+	}
+	if err := validateFormFields(parms.FormFieldsPerDocument, parms.FormFieldGroups, len(parms.Signers), len(parms.File)+len(parms.FileURL)+len(parms.FileIO)); err != nil {
+		return nil, err
+	}
+	if err := validateAttachments(parms.Attachments, len(parms.Signers)); err != nil {
+		return nil, err
+	}
+	if !(&parms).populateFile() {
+		return nil, errors.New("Could not read file io")
+	}
+	draft := &unclaimedDraftRaw{}
+	err := c.postFormAndParse(ctx, "unclaimed_draft/create_embedded", &parms, draft)
+	if err != nil && !isWarning(err) {
+		return nil, err
+	}
+	return &draft.UnclaimedDraft, err
+}
+
+// CreateEmbeddedWithTemplate creates a new draft from one or more templates that can be claimed and
+// used in an embedded iFrame.
+func (c *UnclaimedDraftAPI) CreateEmbeddedWithTemplate(parms UnclaimedDraftEmbTplParms) (*UnclaimedDraft, error) {
+	return c.CreateEmbeddedWithTemplateContext(context.Background(), parms)
+}
+
+// CreateEmbeddedWithTemplateContext is like CreateEmbeddedWithTemplate but uses ctx for the request.
+func (c *UnclaimedDraftAPI) CreateEmbeddedWithTemplateContext(ctx context.Context, parms UnclaimedDraftEmbTplParms) (*UnclaimedDraft, error) {
+	if parms.ClientID == "" || parms.RequesterEmailAddress == "" {
+		return nil, errors.New("Specify client id and requester email address")
+	}
+	if len(parms.TemplateIds) == 0 {
+		return nil, errors.New("Specify template ids, none given")
+	}
+	if parms.hasFile() || parms.hasFileURL() || parms.hasFileIO() {
+		if err := c.validateSigReqFileParms(parms); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateAttachments(parms.Attachments, len(parms.Signers)); err != nil {
+		return nil, err
+	}
+	if !(&parms).populateFile() {
+		return nil, errors.New("Could not read file io")
+	}
+	draft := &unclaimedDraftRaw{}
+	err := c.postFormAndParse(ctx, "unclaimed_draft/create_embedded_with_template", &parms, draft)
+	if err != nil && !isWarning(err) {
+		return nil, err
+	}
+	return &draft.UnclaimedDraft, err
+}
+
+// EditAndResend creates a new draft from an existing signature request, to be edited and sent again
+// in an embedded iFrame. The signature request must have been created with the same api app.
+func (c *UnclaimedDraftAPI) EditAndResend(signatureRequestID string, parms UnclaimedDraftEditParms) (*UnclaimedDraft, error) {
+	return c.EditAndResendContext(context.Background(), signatureRequestID, parms)
+}
+
+// EditAndResendContext is like EditAndResend but uses ctx for the request.
+func (c *UnclaimedDraftAPI) EditAndResendContext(ctx context.Context, signatureRequestID string, parms UnclaimedDraftEditParms) (*UnclaimedDraft, error) {
+	if parms.ClientID == "" {
+		return nil, errors.New("Specify client id")
+	}
+	draft := &unclaimedDraftRaw{}
+	err := c.postFormAndParse(ctx, fmt.Sprintf("unclaimed_draft/edit_and_resend/%s", signatureRequestID), &parms, draft)
+	if err != nil && !isWarning(err) {
+		return nil, err
+	}
+	return &draft.UnclaimedDraft, err
 }
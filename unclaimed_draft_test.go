@@ -0,0 +1,150 @@
+package hellosign_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/StefanNyman/hellosign"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UnclaimedDraftAPI", func() {
+	const draftResp = `{
+		"unclaimed_draft": {
+			"claim_url": "https://app.hellosign.com/send/resendDocs?root_snapshot_guids[]=7f967b7d06e154394eab693febedf61e8ebe49eb&snapshot_access_guids[]=fb848631&response_type=embedded",
+			"signing_redirect_url": "https://example.com/signed",
+			"expires_at": 1414093891,
+			"test_mode": true
+		}
+	}`
+
+	var (
+		api    *hellosign.UnclaimedDraftAPI
+		params map[string]string
+	)
+
+	_ = BeforeEach(func() {
+		api = hellosign.NewUnclaimedDraftAPI("asdf")
+		params = nil
+	})
+
+	respond := func(ept string) {
+		httpmock.RegisterResponder(http.MethodPost, hellosign.GetEptURL(ept),
+			func(req *http.Request) (*http.Response, error) {
+				var err error
+				params, err = parseRequestParameters(req)
+				Expect(err).To(BeNil())
+				return httpmock.NewStringResponse(http.StatusOK, draftResp), nil
+			})
+	}
+
+	expectDraft := func(draft *hellosign.UnclaimedDraft, err error) {
+		Expect(err).To(BeNil())
+		Expect(draft.ClaimURL).To(HavePrefix("https://app.hellosign.com/send/resendDocs"))
+		Expect(*draft.SigningRedirectURL).To(Equal("https://example.com/signed"))
+		Expect(*draft.ExpiresAt).To(Equal(uint64(1414093891)))
+		Expect(draft.TestMode).To(BeTrue())
+	}
+
+	It("creates drafts", func() {
+		respond("unclaimed_draft/create")
+		draft, err := api.Create(hellosign.UnclaimedDraftCreateParms{
+			Type:     hellosign.UnclaimedDraftRequestSignature,
+			Subject:  "Contract",
+			Metadata: map[string]string{"id": "1"},
+			TestMode: 1,
+			FileIO:   []io.Reader{strings.NewReader("%PDF")},
+			Signers: []hellosign.SigReqSigner{
+				{Name: "Jack", EmailAddress: "jack@example.com"},
+			},
+			CCEmailAddresses:   []string{"lawyer@example.com"},
+			SigningRedirectURL: "https://example.com/signed",
+			Attachments:        []hellosign.SignerAttachment{{Name: "Passport", Required: true}},
+		})
+		expectDraft(draft, err)
+		Expect(params["type"]).To(Equal("request_signature"))
+		Expect(params["subject"]).To(Equal("Contract"))
+		Expect(params["metadata[id]"]).To(Equal("1"))
+		Expect(params["test_mode"]).To(Equal("1"))
+		Expect(params["file[0]"]).To(Equal("%PDF"))
+		Expect(params["signers[0][name]"]).To(Equal("Jack"))
+		Expect(params["signers[0][email_address]"]).To(Equal("jack@example.com"))
+		Expect(params["cc_email_addresses[0]"]).To(Equal("lawyer@example.com"))
+		Expect(params["signing_redirect_url"]).To(Equal("https://example.com/signed"))
+		Expect(params["attachments[0][name]"]).To(Equal("Passport"))
+		Expect(params["attachments[0][signer_index]"]).To(Equal("0"))
+		Expect(params["attachments[0][required]"]).To(Equal("1"))
+	})
+
+	It("validates drafts", func() {
+		_, err := api.Create(hellosign.UnclaimedDraftCreateParms{Type: "other", FileURL: []string{"https://example.com/a.pdf"}})
+		Expect(err).ToNot(BeNil())
+		_, err = api.Create(hellosign.UnclaimedDraftCreateParms{Type: hellosign.UnclaimedDraftRequestSignature, FileURL: []string{"https://example.com/a.pdf"}})
+		Expect(err).ToNot(BeNil())
+		_, err = api.Create(hellosign.UnclaimedDraftCreateParms{Type: hellosign.UnclaimedDraftSendDocument})
+		Expect(err).ToNot(BeNil())
+		_, err = api.Create(hellosign.UnclaimedDraftCreateParms{
+			Type:        hellosign.UnclaimedDraftRequestSignature,
+			FileURL:     []string{"https://example.com/a.pdf"},
+			Signers:     []hellosign.SigReqSigner{{Name: "Jack", EmailAddress: "jack@example.com"}},
+			Attachments: []hellosign.SignerAttachment{{Name: "Passport", SignerIndex: 1}},
+		})
+		Expect(err).ToNot(BeNil())
+		_, err = api.CreateEmbedded(hellosign.UnclaimedDraftEmbCreateParms{FileURL: []string{"https://example.com/a.pdf"}})
+		Expect(err).ToNot(BeNil())
+		_, err = api.CreateEmbeddedWithTemplate(hellosign.UnclaimedDraftEmbTplParms{ClientID: "id", RequesterEmailAddress: "me@example.com"})
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("creates embedded drafts", func() {
+		respond("unclaimed_draft/create_embedded")
+		draft, err := api.CreateEmbedded(hellosign.UnclaimedDraftEmbCreateParms{
+			ClientID:              "client-id",
+			RequesterEmailAddress: "me@example.com",
+			FileURL:               []string{"https://example.com/a.pdf"},
+			IsForEmbeddedSigning:  1,
+		})
+		expectDraft(draft, err)
+		Expect(params["client_id"]).To(Equal("client-id"))
+		Expect(params["requester_email_address"]).To(Equal("me@example.com"))
+		Expect(params["file_url[0]"]).To(Equal("https://example.com/a.pdf"))
+		Expect(params["is_for_embedded_signing"]).To(Equal("1"))
+		Expect(params).ToNot(HaveKey("type"))
+	})
+
+	It("creates embedded drafts with templates", func() {
+		respond("unclaimed_draft/create_embedded_with_template")
+		draft, err := api.CreateEmbeddedWithTemplate(hellosign.UnclaimedDraftEmbTplParms{
+			ClientID:              "client-id",
+			RequesterEmailAddress: "me@example.com",
+			TemplateIds:           []string{"tpl-1"},
+			Signers: map[string]hellosign.SigReqSendTplParmsSigner{
+				"Client": {Name: "Jack", EmailAddress: "jack@example.com"},
+			},
+			Ccs: map[string]hellosign.SigReqSendTplParmsCcs{
+				"Accounting": {EmailAddress: "accounting@example.com"},
+			},
+			CustomFields: `[{"name": "Cost", "value": "$20,000"}]`,
+		})
+		expectDraft(draft, err)
+		Expect(params["template_ids[0]"]).To(Equal("tpl-1"))
+		Expect(params["signers[Client][name]"]).To(Equal("Jack"))
+		Expect(params["signers[Client][email_address]"]).To(Equal("jack@example.com"))
+		Expect(params["ccs[Accounting][email_address]"]).To(Equal("accounting@example.com"))
+		Expect(params["custom_fields"]).To(Equal(`[{"name": "Cost", "value": "$20,000"}]`))
+	})
+
+	It("edits and resends signature requests", func() {
+		respond("unclaimed_draft/edit_and_resend/sig-req-1")
+		draft, err := api.EditAndResend("sig-req-1", hellosign.UnclaimedDraftEditParms{
+			ClientID: "client-id",
+			TestMode: 1,
+		})
+		expectDraft(draft, err)
+		Expect(params["client_id"]).To(Equal("client-id"))
+		Expect(params["test_mode"]).To(Equal("1"))
+	})
+})